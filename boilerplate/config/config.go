@@ -6,16 +6,9 @@
 package config
 
 import (
-	"log"
 	"os"
-	"regexp"
 	"strconv"
-	"strings"
 	"time"
-
-	"github.com/cuttle-ai/web-starter/boilerplate/version"
-
-	"github.com/cuttle-ai/configs/config"
 )
 
 var (
@@ -33,63 +26,8 @@ var (
 	RequestCleanUpCheck = time.Duration(2 * time.Minute)
 )
 
-//SkipVault will skip the vault initialization if set true
-var SkipVault bool
-
 //IsTest indicates that the current runtime is for test
-var IsTest bool
-
-func init() {
-	/*
-	 * Based on the env variables will set the
-	 *	* SkipVault
-	 *  * IsTest
-	 */
-	sk := os.Getenv("SKIP_VAULT")
-	if sk == "true" {
-		SkipVault = true
-	}
-	iT := os.Getenv("IS_TEST")
-	if iT == "true" {
-		IsTest = true
-	}
-}
-
-func init() {
-	/*
-	 * We will load the config from secrets management service
-	 * Then we will set them as environment variables
-	 */
-	//getting the configuration
-	log.Println("Getting the config values from vault")
-	if SkipVault {
-		return
-	}
-	v, err := config.NewVault()
-	checkError(err)
-	reg, err := regexp.Compile("[^A-Za-z0-9]+")
-	if err != nil {
-		log.Fatal(err)
-	}
-	configName := strings.ToLower(reg.ReplaceAllString(version.AppName, "-"))
-	if IsTest {
-		configName += "-test"
-	}
-	config, err := v.GetConfig(configName)
-	checkError(err)
-
-	//setting the configs as environment variables
-	for k, v := range config {
-		log.Println("Setting the secret from vault", k)
-		os.Setenv(k, v)
-	}
-}
-
-func checkError(err error) {
-	if err != nil {
-		log.Fatal(err)
-	}
-}
+var IsTest = os.Getenv("IS_TEST") == "true"
 
 func init() {
 	/*
@@ -0,0 +1,63 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"log"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/cuttle-ai/web-starter/boilerplate/version"
+
+	"github.com/cuttle-ai/configs/config"
+)
+
+/* This file contains the loading of the secrets from vault */
+
+//SkipVault will skip the vault initialization if set true
+var SkipVault = os.Getenv("SKIP_VAULT") == "true"
+
+//secrets are the config values loaded from vault. Being a package variable, they are loaded and set as
+//environment variables before the init funcs of the package read the configuration from the environment
+var secrets = loadSecrets()
+
+//loadSecrets will load the config from secrets management service and set them as environment variables
+func loadSecrets() map[string]string {
+	/*
+	 * We will load the config from secrets management service
+	 * Then we will set them as environment variables
+	 */
+	//getting the configuration
+	log.Println("Getting the config values from vault")
+	if SkipVault {
+		return nil
+	}
+	v, err := config.NewVault()
+	checkError(err)
+	reg, err := regexp.Compile("[^A-Za-z0-9]+")
+	if err != nil {
+		log.Fatal(err)
+	}
+	configName := strings.ToLower(reg.ReplaceAllString(version.AppName, "-"))
+	if IsTest {
+		configName += "-test"
+	}
+	config, err := v.GetConfig(configName)
+	checkError(err)
+
+	//setting the configs as environment variables
+	for k, v := range config {
+		log.Println("Setting the secret from vault", k)
+		os.Setenv(k, v)
+	}
+	return config
+}
+
+func checkError(err error) {
+	if err != nil {
+		log.Fatal(err)
+	}
+}
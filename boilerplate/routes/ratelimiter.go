@@ -116,7 +116,28 @@ func CleanUpCheck(in chan AppContextRequest) {
 	}
 }
 
+//acquireFromPool gets an app context for a request from the app context go routine.
+//It returns false if the app contexts have exhausted.
+func acquireFromPool() (*config.AppContext, bool) {
+	req := AppContextRequest{
+		Type: Get,
+		Out:  make(chan AppContextRequest),
+	}
+	go SendRequest(AppContextRequestChan, req)
+	res := <-req.Out
+	return res.AppContext, !res.Exhausted
+}
+
+//returnToPool returns the app context of a request to the app context go routine
+func returnToPool(a *config.AppContext) {
+	go SendRequest(AppContextRequestChan, AppContextRequest{
+		Type:       Finished,
+		AppContext: a,
+	})
+}
+
 func init() {
 	go AppContext(AppContextRequestChan)
 	go CleanUpCheck(AppContextRequestChan)
+	acquireAppContext, releaseAppContext = acquireFromPool, returnToPool
 }
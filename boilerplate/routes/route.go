@@ -7,6 +7,7 @@ package routes
 import (
 	"context"
 	"net/http"
+	"sync/atomic"
 
 	"github.com/cuttle-ai/web-starter/boilerplate/log"
 	"github.com/cuttle-ai/web-starter/boilerplate/routes/response"
@@ -38,6 +39,19 @@ type Route struct {
 //AppContextKey is the key with which the application is saved in the request context
 const AppContextKey = "app-context"
 
+//requestID is the id of the last request given an app context by the default acquireAppContext
+var requestID int64
+
+//acquireAppContext returns the app context for a request. It returns false if the server can't serve
+//any more requests at the moment. By default every request gets a new app context.
+//The rate limiter replaces it to limit the no. of requests being served at a given point of time.
+var acquireAppContext = func() (*config.AppContext, bool) {
+	return config.NewAppContext(log.NewLogger(int(atomic.AddInt64(&requestID, 1)))), true
+}
+
+//releaseAppContext returns the app context of a request once it is served
+var releaseAppContext = func(a *config.AppContext) {}
+
 //Register registers the route with the default http handler func
 func (r Route) Register(s *http.ServeMux) {
 	/*
@@ -78,15 +92,10 @@ func (r Route) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 	}
 
 	//fetching the app context
-	appCtxReq := AppContextRequest{
-		Type: Get,
-		Out:  make(chan AppContextRequest),
-	}
-	go SendRequest(AppContextRequestChan, appCtxReq)
-	resCtx := <-appCtxReq.Out
+	appCtx, ok := acquireAppContext()
 
 	//checking whether the app context exhausted or not
-	if resCtx.Exhausted {
+	if !ok {
 		//reject the request
 		log.Error("We have exhausted the request limits")
 		response.WriteError(res, response.Error{Err: "We have exhuasted the server request limits. Please try after some time."}, http.StatusTooManyRequests)
@@ -96,17 +105,13 @@ func (r Route) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 	}
 
 	//setting the app context
-	newCtx := context.WithValue(ctx, AppContextKey, resCtx.AppContext)
+	newCtx := context.WithValue(ctx, AppContextKey, appCtx)
 
 	//executing the request
 	r.Exec(newCtx, res, req)
 
 	//returning the app context
-	appCtxReq = AppContextRequest{
-		Type:       Finished,
-		AppContext: resCtx.AppContext,
-	}
-	go SendRequest(AppContextRequestChan, appCtxReq)
+	releaseAppContext(appCtx)
 }
 
 //Exec will execute the handler func. By default it will set response content type as as json.
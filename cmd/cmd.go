@@ -29,6 +29,13 @@ var rootCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(upgradeCmd)
+	rootCmd.AddCommand(regenerateCmd)
+	rootCmd.AddCommand(licensesCmd)
+	rootCmd.AddCommand(routeCmd)
+	rootCmd.AddCommand(handlerCmd)
+	rootCmd.AddCommand(modelCmd)
+	rootCmd.AddCommand(openAPICmd)
 	rootCmd.AddCommand(web_server.WebServerCmd)
 }
 
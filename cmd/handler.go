@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cuttle-ai/web-starter/generate"
+	"github.com/cuttle-ai/web-starter/project"
+	"github.com/spf13/cobra"
+)
+
+/* This file contains the handler command of the application adding handlers to a generated project */
+
+//handlerFlags has the handler given through the command line flags
+var handlerFlags = project.RouteSpec{}
+
+func init() {
+	handlerAddCmd.Flags().StringVar(&handlerFlags.Handler, "name", "", "Name of the handler func like ListUsers")
+	handlerAddCmd.Flags().StringVar(&handlerFlags.Method, "method", "GET", "HTTP method handled by the handler")
+	handlerAddCmd.Flags().StringVar(&handlerFlags.Pattern, "pattern", "", "URL pattern the handler is meant for. Used in its docs and test")
+	handlerCmd.AddCommand(handlerAddCmd)
+}
+
+var handlerCmd = &cobra.Command{
+	Use:   "handler",
+	Short: "Manages the handlers of a generated project",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := cmd.Help()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
+
+var handlerAddCmd = &cobra.Command{
+	Use:   "add [project directory]",
+	Short: "Adds a handler to a generated project",
+	Long: `Adds a handler func to the routes package of a project generated by web-starter along with a table driven test.
+Unlike route add, the handler isn't added to the routes of the project. So it can be registered in a route as required.
+The pattern defaults to the name of the handler like /list-users. The project directory defaults to the current directory.
+
+  web-starter handler add --name ListUsers --method GET`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		h := handlerFlags
+		if len(h.Handler) == 0 && len(h.Pattern) == 0 {
+			fmt.Println("--name or --pattern of the handler is required")
+			os.Exit(1)
+		}
+		if len(h.Pattern) == 0 {
+			h.Pattern = "/" + generate.KebabCase(h.Handler)
+		}
+		dir, err := projectDir(args)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		files, err := project.AddRoute(dir, h)
+		if err != nil {
+			//Error while adding the handler
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		printAdded(files)
+		os.Exit(0)
+	},
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/cuttle-ai/web-starter/deps"
+	"github.com/cuttle-ai/web-starter/project"
+	"github.com/spf13/cobra"
+)
+
+/* This file contains the licenses command of the application */
+
+//licensesFormat is the output format of the license report given through the command line flag
+var licensesFormat string
+
+//projectLicense is the SPDX identifier of the project license given through the command line flag
+var projectLicense string
+
+func init() {
+	licensesCmd.Flags().StringVar(&licensesFormat, "format", "table", "Output format of the report. table or json")
+	licensesCmd.Flags().StringVar(&projectLicense, "license", "",
+		"SPDX identifier of the project license. Defaults to the one recorded in the project's .web-starter.json")
+}
+
+var licensesCmd = &cobra.Command{
+	Use:   "licenses [project directory]",
+	Short: "Reports the licenses of the dependencies of a generated project",
+	Long: `Reports the licenses of the dependencies of a generated project.
+The modules required by the project's go.mod are listed with go list and their licenses are detected from the
+license files in the module cache. Dependencies whose license conflicts with the license of the project, like
+AGPL code in a CLOSED project, are flagged. Run go mod download before so that all the modules are in the cache.
+The command exits with a non zero status if there are conflicts. The project directory defaults to the current directory.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		/*
+		 * We will get the project directory and its license
+		 * Then we will inspect the dependencies of the project
+		 * Then we will print the report in the format asked for
+		 */
+		//getting the project directory and its license
+		if licensesFormat != "table" && licensesFormat != "json" {
+			fmt.Println("unknown format", licensesFormat, "Use table or json")
+			os.Exit(1)
+		}
+		dir, err := projectDir(args)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		license := projectLicense
+		if len(license) == 0 {
+			m, err := project.ReadMetadata(dir)
+			if err != nil {
+				//Error while reading the license of the project
+				fmt.Println(err, "Use --license to give the license of the project")
+				os.Exit(1)
+			}
+			license = m.SPDX
+		}
+
+		//inspecting the dependencies
+		report, err := deps.Inspect(dir, license)
+		if err != nil {
+			//Error while inspecting the dependencies of the project
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		//printing the report
+		if licensesFormat == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(report)
+		} else {
+			err = printLicenses(report)
+		}
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if len(report.Conflicts()) > 0 {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
+
+//printLicenses prints the license report as a table
+func printLicenses(report *deps.Report) error {
+	fmt.Println("Project", report.Module, "is licensed under", report.License)
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "MODULE\tVERSION\tLICENSE\tSTATUS\tNOTE")
+	for _, v := range report.Dependencies {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", v.Path, v.Version, v.License, v.Status, v.Note)
+	}
+	err := w.Flush()
+	if err != nil {
+		return err
+	}
+	if c := report.Conflicts(); len(c) > 0 {
+		fmt.Println()
+		fmt.Println(len(c), "dependencies have licenses conflicting with the", report.License, "license of the project")
+	}
+	return nil
+}
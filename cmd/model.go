@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cuttle-ai/web-starter/project"
+	"github.com/spf13/cobra"
+)
+
+/* This file contains the model command of the application adding models to a generated project */
+
+//modelName is the name of the model given through the command line flag
+var modelName string
+
+//modelFields are the fields of the model given through the command line flag
+var modelFields string
+
+//modelVersion is the api version of the CRUD routes of the model given through the command line flag
+var modelVersion string
+
+//modelPattern is the url pattern of the CRUD routes of the model given through the command line flag
+var modelPattern string
+
+func init() {
+	modelAddCmd.Flags().StringVar(&modelName, "name", "", "Name of the model like User")
+	modelAddCmd.Flags().StringVar(&modelFields, "fields", "", "Fields of the model as name:type pairs like name:string,age:int. "+
+		"The types can be string, bool, int, int32, int64, uint, float32, float64 or time")
+	modelAddCmd.Flags().StringVar(&modelVersion, "version", "v1", "API version of the CRUD routes of the model")
+	modelAddCmd.Flags().StringVar(&modelPattern, "pattern", "", "URL pattern of the CRUD routes. Defaults to the plural of the model like /users")
+	modelCmd.AddCommand(modelAddCmd)
+}
+
+var modelCmd = &cobra.Command{
+	Use:   "model",
+	Short: "Manages the database models of a generated project",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := cmd.Help()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
+
+var modelAddCmd = &cobra.Command{
+	Use:   "add [project directory]",
+	Short: "Adds a gorm model and its CRUD routes to a generated project",
+	Long: `Adds a gorm model to the models package of a project generated by web-starter with the database component.
+The CRUD routes of the model are added to the routes of the project using the database connection of the app context.
+GET lists the models or gets the one with the id in the query, POST creates one, PUT updates and DELETE deletes
+the one with the id in the query. The project directory defaults to the current directory.
+
+  web-starter model add --name User --fields name:string,email:string`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(modelName) == 0 {
+			fmt.Println("--name of the model is required")
+			os.Exit(1)
+		}
+		fields, err := project.ParseFields(modelFields)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		dir, err := projectDir(args)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		files, err := project.AddModel(dir, project.ModelSpec{Name: modelName, Fields: fields}, modelVersion, modelPattern)
+		if err != nil {
+			//Error while adding the model
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		printAdded(files)
+		os.Exit(0)
+	},
+}
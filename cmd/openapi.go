@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cuttle-ai/web-starter/project"
+	"github.com/spf13/cobra"
+)
+
+/* This file contains the openapi command of the application generating the routes of a project from its OpenAPI document */
+
+//openAPIDoc is the OpenAPI document given through the command line flag
+var openAPIDoc string
+
+func init() {
+	openAPISyncCmd.Flags().StringVar(&openAPIDoc, "spec", "", "OpenAPI 3 document in yaml or json. Defaults to the one recorded in the project")
+	openAPICmd.AddCommand(openAPISyncCmd)
+}
+
+var openAPICmd = &cobra.Command{
+	Use:   "openapi",
+	Short: "Generates the routes of a generated project from its OpenAPI document",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := cmd.Help()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
+
+var openAPISyncCmd = &cobra.Command{
+	Use:   "sync [project directory]",
+	Short: "Generates the routes of a generated project from its OpenAPI document again",
+	Long: `Generates the types of the requests and responses in the api package, and the routes of the paths along with a
+stub for every operation in the routes package of a project generated by web-starter from its OpenAPI 3 document.
+The document given with --spec is recorded in the project and used when it isn't given. The types and routes are
+generated again on every run. Stubs are only added for the new operations, so the handler bodies written in them are
+left as they are. Stubs whose signature doesn't match their operation anymore are listed to be updated and the
+stubs of the operations removed from or renamed in the document are listed as orphaned to be deleted.
+The project directory defaults to the current directory.
+
+  web-starter openapi sync --spec api.yaml`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, err := projectDir(args)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		s, err := project.SyncOpenAPI(dir, openAPIDoc)
+		if err != nil {
+			//Error while generating the routes from the OpenAPI document
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Print(s)
+		if len(s.Outdated) > 0 || len(s.Orphaned) > 0 {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cuttle-ai/web-starter/project"
+	"github.com/spf13/cobra"
+)
+
+/* This file contains the regenerate command of the application */
+
+//regenerateFlags has the answers to be overridden given through the command line flags
+var regenerateFlags = project.Project{}
+
+//regenerateLicenseType is the license type to be overridden given through the command line flag
+var regenerateLicenseType string
+
+func init() {
+	f := regenerateCmd.Flags()
+	f.StringVar(&regenerateFlags.Name, "name", "", "Name of the project")
+	f.StringVar(&regenerateFlags.Description, "description", "", "Description of the project")
+	f.StringVar(&regenerateFlags.Author.Name, "author-name", "", "Name of the author")
+	f.StringVar(&regenerateFlags.Author.Email, "author-email", "", "Email of the author")
+	f.StringVar(&regenerateFlags.Package, "package", "", "Package path of the project")
+	f.StringVar(&regenerateLicenseType, "license-type", "", "Type of the license")
+	f.StringVar(&regenerateFlags.License.Year, "license-year", "", "Copyright year of the license")
+	f.StringVar(&regenerateFlags.License.Organisation, "license-organisation", "", "Organisation issuing the license")
+	f.StringVar(&regenerateFlags.LicensesDir, "licenses-dir", "", "Directory having additional license templates as <type>/LICENSE")
+}
+
+var regenerateCmd = &cobra.Command{
+	Use:   "regenerate [project directory]",
+	Short: "Generates a project again with its recorded answers",
+	Long: `Generates a project again with the answers recorded in its .web-starter.json.
+The answers given as flags override the recorded ones. The changes due to the new answers are merged into the files
+of the project, so that renaming the project or switching its license is a one-liner:
+  web-starter regenerate --name "Orders" --package github.com/acme/orders
+  web-starter regenerate --license-type BSD-3
+The project is generated from the same version of the template it was generated from. Use upgrade for newer versions.
+The project directory defaults to the current directory.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		/*
+		 * We will get the project directory
+		 * Then we will regenerate the project with the overridden answers
+		 * Then we will print the summary
+		 */
+		//getting the project directory
+		dir, err := projectDir(args)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		//regenerating the project
+		overrides := regenerateFlags
+		overrides.License.Type = project.LicenseType(regenerateLicenseType)
+		if len(overrides.LicensesDir) > 0 {
+			overrides.LicensesDir, err = filepath.Abs(overrides.LicensesDir)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+		report, err := project.Regenerate(dir, overrides)
+		if err != nil {
+			//Error while regenerating the project
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		//printing the summary
+		fmt.Println("Regenerated with", report.To)
+		if !printReport(report) {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cuttle-ai/web-starter/project"
+	"github.com/spf13/cobra"
+)
+
+/* This file contains the route command of the application adding routes to a generated project */
+
+//routeFlags has the route given through the command line flags
+var routeFlags = project.RouteSpec{}
+
+func init() {
+	routeAddCmd.Flags().StringVar(&routeFlags.Version, "version", "v1", "API version of the route")
+	routeAddCmd.Flags().StringVar(&routeFlags.Pattern, "pattern", "", "URL pattern of the route like /users")
+	routeAddCmd.Flags().StringVar(&routeFlags.Method, "method", "GET", "HTTP method handled by the route")
+	routeAddCmd.Flags().StringVar(&routeFlags.Handler, "name", "", "Name of the handler func. Defaults to the method and the pattern like GetUsers")
+	routeCmd.AddCommand(routeAddCmd)
+}
+
+var routeCmd = &cobra.Command{
+	Use:   "route",
+	Short: "Manages the routes of a generated project",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := cmd.Help()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
+
+var routeAddCmd = &cobra.Command{
+	Use:   "add [project directory]",
+	Short: "Adds a route to a generated project",
+	Long: `Adds a route to a project generated by web-starter.
+A handler of the route is added in the routes package along with a table driven test. The handler is added to the
+routes of the project with AddRoutes in the init of its file. The http mux doesn't allow a pattern to be registered twice,
+so a route whose pattern already exists isn't added. The project directory defaults to the current directory.
+
+  web-starter route add --version v1 --pattern /users --method GET`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(routeFlags.Pattern) == 0 {
+			fmt.Println("--pattern of the route is required")
+			os.Exit(1)
+		}
+		dir, err := projectDir(args)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		r := routeFlags
+		r.Register = true
+		files, err := project.AddRoute(dir, r)
+		if err != nil {
+			//Error while adding the route
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		printAdded(files)
+		os.Exit(0)
+	},
+}
+
+//printAdded prints the files added to the project
+func printAdded(files []string) {
+	for _, v := range files {
+		fmt.Printf("%-8s %s\n", "added", v)
+	}
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cuttle-ai/web-starter/project"
+	"github.com/spf13/cobra"
+)
+
+/* This file contains the upgrade command of the application */
+
+//upgradeTemplate is the template to which the project has to be upgraded given through the command line flag
+var upgradeTemplate string
+
+func init() {
+	upgradeCmd.Flags().StringVar(&upgradeTemplate, "template", "",
+		"Template to upgrade the project to. Defaults to the latest version of the template the project was generated from")
+}
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade [project directory]",
+	Short: "Re-applies the newer boilerplate to a generated project",
+	Long: `Re-applies the newer version of the template to a project generated by web-starter.
+The version of the template the project was generated from and the newer one are rendered with the answers
+recorded in the project's .web-starter.json. Then the changes between them are merged into the files of the project.
+Conflicting changes are marked in the files like git does. The project directory defaults to the current directory.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		/*
+		 * We will get the project directory
+		 * Then we will upgrade the project
+		 * Then we will print the summary
+		 */
+		//getting the project directory
+		dir, err := projectDir(args)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		//upgrading the project
+		var to *project.Template
+		if len(upgradeTemplate) > 0 {
+			t := project.ParseTemplate(upgradeTemplate)
+			to = &t
+		}
+		report, err := project.Upgrade(dir, to)
+		if err != nil {
+			//Error while upgrading the project
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		//printing the summary
+		fmt.Println("Upgraded from", report.From, "to", report.To)
+		if !printReport(report) {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	},
+}
+
+//printReport prints the files changed by the upgrade or regeneration of a project followed by the files
+//having conflicts. It returns false if there are conflicts.
+func printReport(report *project.UpgradeReport) bool {
+	for _, v := range report.Files {
+		if v.Status != project.FileUnchanged {
+			fmt.Printf("%-22s %s\n", v.Status, v.Path)
+		}
+	}
+	conflicts := report.Conflicts()
+	if len(conflicts) == 0 {
+		return true
+	}
+	fmt.Println()
+	fmt.Println("The following files have conflicts. Resolve the conflict markers in them:")
+	for _, v := range conflicts {
+		fmt.Println(" ", v.Path, "-", v.Conflicts, "conflict(s)")
+	}
+	return false
+}
+
+//projectDir returns the absolute path of the project directory given in the arguments. It defaults to the current directory.
+func projectDir(args []string) (string, error) {
+	if len(args) == 0 {
+		return filepath.Abs(".")
+	}
+	return filepath.Abs(args[0])
+}
@@ -0,0 +1,65 @@
+package web_server
+
+import (
+	"fmt"
+
+	"github.com/cuttle-ai/web-starter/project"
+	"github.com/tcnksm/go-input"
+)
+
+/* This file contains the interactive resolution of the conflicts with the files existing in the destination */
+
+const (
+	//keepFile is the choice for keeping the existing file
+	keepFile = "keep"
+	//overwriteFile is the choice for overwriting the existing file
+	overwriteFile = "overwrite"
+	//diffFile is the choice for showing the diff of the existing file and the generated one
+	diffFile = "diff"
+)
+
+//resolveConflicts asks the user whether to keep or overwrite each of the files which already exist in
+//the project destination. The user can also ask for the diff before choosing.
+func resolveConflicts(ui *input.UI, pr *project.Project) error {
+	/*
+	 * We will get the conflicts
+	 * Then we will get the plan for showing the diff
+	 * Then we will ask the user for each conflicting file
+	 */
+	//getting the conflicts
+	conflicts, err := pr.Conflicts()
+	if err != nil || len(conflicts) == 0 {
+		return err
+	}
+
+	//getting the plan
+	pr.OnConflict = project.Overwrite
+	plan, err := pr.Plan()
+	if err != nil {
+		return err
+	}
+	diffs := map[string]string{}
+	for _, v := range plan.Files {
+		diffs[v.Path] = v.Diff
+	}
+
+	//asking the user for each conflicting file
+	pr.Keep = map[string]bool{}
+	for _, v := range conflicts {
+		choice := diffFile
+		for choice == diffFile {
+			choice, err = ui.Select(v+" already exists", []string{keepFile, overwriteFile, diffFile}, &input.Options{
+				Default:  keepFile,
+				Required: true,
+			})
+			if err != nil {
+				return err
+			}
+			if choice == diffFile {
+				fmt.Print(diffs[v])
+			}
+		}
+		pr.Keep[v] = choice == keepFile
+	}
+	return nil
+}
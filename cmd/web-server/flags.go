@@ -0,0 +1,164 @@
+package web_server
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cuttle-ai/web-starter/project"
+)
+
+/* This file contains the flags of the generate command */
+
+//projectFlags has the project details given through the command line flags
+var projectFlags = project.Project{}
+
+//licenseType is the license type given through the command line flag
+var licenseType string
+
+//templateArg is the template given through the command line flag
+var templateArg string
+
+//configFile is the project spec file from which the project details are read
+var configFile string
+
+//dryRun will print the changes the generate command would make instead of making them
+var dryRun bool
+
+//force will overwrite the files already existing in the destination
+var force bool
+
+//skipExisting will keep the files already existing in the destination
+var skipExisting bool
+
+//interactive will ask the user whether to keep or overwrite each of the files already existing in the destination
+var interactive bool
+
+//skipSteps are the post generation steps to be skipped
+var skipSteps []string
+
+//goFlags are the GOFLAGS with which the go commands of the post generation steps are run
+var goFlags string
+
+//components are the optional components chosen through the command line flag
+var components []string
+
+//openAPIDoc is the OpenAPI document from which the routes of the project are generated
+var openAPIDoc string
+
+//assumeYes will make the generate command take the defaults instead of prompting the user
+var assumeYes bool
+
+func init() {
+	f := generateCmd.Flags()
+	f.StringVar(&projectFlags.Name, "name", "", "Name of the project")
+	f.StringVar(&projectFlags.Description, "description", "", "Description of the project")
+	f.StringVar(&projectFlags.Author.Name, "author-name", "", "Name of the author")
+	f.StringVar(&projectFlags.Author.Email, "author-email", "", "Email of the author")
+	f.StringVar(&projectFlags.Destination, "destination", "", "Directory in which the project has to be generated")
+	f.StringVar(&projectFlags.Package, "package", "", "Package path of the project")
+	f.StringVar(&licenseType, "license-type", "", "Type of the license")
+	f.StringVar(&projectFlags.License.Year, "license-year", "", "Copyright year of the license")
+	f.StringVar(&projectFlags.License.Organisation, "license-organisation", "", "Organisation issuing the license")
+	f.StringVar(&projectFlags.LicensesDir, "licenses-dir", "", "Directory having additional license templates as <type>/LICENSE")
+	f.StringVar(&templateArg, "template", "", "Template to generate the project from. A local directory or a git url with an optional @ref")
+	f.StringVar(&configFile, "config", "", "Project spec file (yaml or json) with the project details")
+	f.BoolVar(&dryRun, "dry-run", false, "Print the files, refactors and diff of the project without writing anything")
+	f.BoolVar(&force, "force", false, "Overwrite the files already existing in the destination")
+	f.BoolVar(&skipExisting, "skip-existing", false, "Keep the files already existing in the destination")
+	f.BoolVarP(&interactive, "interactive", "i", false, "Ask whether to keep, overwrite or diff each file already existing in the destination")
+	f.BoolVarP(&assumeYes, "yes", "y", false, "Take the defaults for the missing project details instead of prompting")
+	f.StringSliceVar(&skipSteps, "skip", nil, "Post generation steps to be skipped. Any of "+
+		strings.Join(project.Steps, ", ")+" or all")
+	f.StringSliceVar(&components, "components", nil, "Optional components of the project. Any of "+
+		strings.Join(project.ComponentNames, ", ")+" or none. All of them are included by default")
+	f.StringVar(&openAPIDoc, "openapi", "", "OpenAPI 3 document in yaml or json to generate the types, routes and handler stubs of the project from")
+	f.StringVar(&goFlags, "goflags", "", "GOFLAGS for the go commands run after generation. For example -mod=mod or -mod=vendor")
+}
+
+//projectFromFlags returns the project details given through the flags and the spec file.
+//Values given as flags take precedence over the ones in the spec file.
+func projectFromFlags() (*project.Project, error) {
+	/*
+	 * We will take the values from the flags
+	 * We will choose the components if given
+	 * We will set the conflict policy from the flags
+	 * Then we will fill the rest from the spec file if given
+	 */
+	if force && skipExisting {
+		return nil, errors.New("--force and --skip-existing can't be used together")
+	}
+	if interactive && assumeYes {
+		return nil, errors.New("--interactive and --yes can't be used together. Use --force or --skip-existing to handle the existing files with --yes")
+	}
+	if interactive && (force || skipExisting) {
+		return nil, errors.New("--interactive can't be used together with --force or --skip-existing since it asks what to do with each existing file")
+	}
+	pr := projectFlags
+	pr.License.Type = project.LicenseType(licenseType)
+	if len(pr.LicensesDir) > 0 {
+		dir, err := filepath.Abs(pr.LicensesDir)
+		if err != nil {
+			return nil, err
+		}
+		pr.LicensesDir = dir
+	}
+	if len(templateArg) > 0 {
+		pr.Template = project.ParseTemplate(templateArg)
+	}
+	if len(components) > 0 {
+		c, err := project.ParseComponents(components)
+		if err != nil {
+			return nil, err
+		}
+		pr.Components = c
+	}
+	pr.OnConflict = project.Fail
+	if force {
+		pr.OnConflict = project.Overwrite
+	}
+	if skipExisting {
+		pr.OnConflict = project.SkipExisting
+	}
+
+	//reading the spec file
+	if len(configFile) == 0 {
+		return &pr, nil
+	}
+	spec, err := project.Load(configFile)
+	if err != nil {
+		//error while loading the spec file
+		fmt.Println("Error while loading the project spec from", configFile)
+		return nil, err
+	}
+	//the OpenAPI document of the spec is validated and recorded relative to the destination like the one given with --openapi
+	if len(openAPIDoc) == 0 {
+		openAPIDoc = spec.OpenAPI
+	}
+	spec.OpenAPI = ""
+	pr.Merge(*spec)
+	return &pr, nil
+}
+
+//postStepsFromFlags returns the post generation steps configured through the flags
+func postStepsFromFlags() (project.PostSteps, error) {
+	steps := project.PostSteps{Skip: map[string]bool{}, GoFlags: goFlags}
+	known := map[string]bool{}
+	for _, v := range project.Steps {
+		known[v] = true
+	}
+	for _, v := range skipSteps {
+		if v == "all" {
+			for _, s := range project.Steps {
+				steps.Skip[s] = true
+			}
+			continue
+		}
+		if !known[v] {
+			return steps, fmt.Errorf("unknown post generation step %s in --skip. Use any of %s or all", v, strings.Join(project.Steps, ", "))
+		}
+		steps.Skip[v] = true
+	}
+	return steps, nil
+}
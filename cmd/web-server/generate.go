@@ -3,7 +3,7 @@ package web_server
 import (
 	"fmt"
 	"os"
-	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -22,14 +22,24 @@ func init() {
 var generateCmd = &cobra.Command{
 	Use:   "generate",
 	Short: "Generates the boiler plate code for the web-server",
-	Long:  `All software has versions. This is web-starter's`,
+	Long: `Generates the boiler plate code for the web-server.
+The project details can be given as flags or through a project spec file using --config.
+Details which are still missing will be prompted for. With --yes the defaults are taken for them
+and nothing is read from the standard input.
+The types, routes and handler stubs of an OpenAPI 3 document given with --openapi are generated in the project.
+After generation go modules are initialized and tidied, a git repository is initialized with the first commit
+and the project is built and tested. Use --skip to skip any of these steps.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		/*
 		 * We will initiate the input
-		 * Then we will the user for project details
+		 * Then we will read the project details from the flags and spec file
+		 * Then we will resolve the template
+		 * Then we will ask the user for missing project details
+		 * Then we will validate the project details and the OpenAPI document
+		 * Then we will ask the user what to do with the existing files if interactive
 		 * Then will generate the project
-		 * Then init the go mod
-		 * Then we will install it
+		 * Then we will generate the routes from the OpenAPI document if given
+		 * Then we will run the post generation steps
 		 */
 		//initializing the UI
 		ui := &input.UI{
@@ -37,16 +47,76 @@ var generateCmd = &cobra.Command{
 			Reader: os.Stdin,
 		}
 
-		//prompting the user for project details
-		pr, err := prompts(ui)
+		//reading the project details given as flags
+		pr, err := projectFromFlags()
+		if err != nil {
+			//Error while reading the project details from the flags
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		steps, err := postStepsFromFlags()
+		if err != nil {
+			//Error while reading the post generation steps from the flags
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		//resolving the template
+		err = pr.Template.Resolve()
+		if err != nil {
+			//Error while resolving the template
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		//prompting the user for missing project details
+		err = prompts(ui, pr, assumeYes)
 		if err != nil {
 			//Error while promoting the user for inputs of project generation
 			fmt.Println(err)
 			os.Exit(1)
 		}
 
+		//validating the project details
+		err = pr.Validate()
+		if err != nil {
+			//Error while validating the project details
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		//recording the OpenAPI document
+		if len(openAPIDoc) > 0 {
+			err = pr.SetOpenAPI(openAPIDoc)
+			if err != nil {
+				//Error while reading the OpenAPI document
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+
+		//asking the user what to do with the existing files
+		if interactive {
+			err = resolveConflicts(ui, pr)
+			if err != nil {
+				//Error while resolving the conflicts with the existing files
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+
+		//printing the plan if it is a dry run
+		if dryRun {
+			err = printPlan(pr)
+			if err != nil {
+				//Error while planning the project
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+
 		//generating the project
-		pr.InitSources()
 		err = pr.Setup()
 		if err != nil {
 			//Error while genertaing the project
@@ -54,29 +124,21 @@ var generateCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		//if the path isn't in the go path, then add go modules to it
-		if strings.Index(pr.Destination, project.GoPath()) < 0 {
-			c := exec.Command("go", "mod", "init", pr.Package)
-			c.Dir = pr.Destination
-			c.Stderr = os.Stderr
-			fmt.Println("Initializing go modules since the project isn't in GOPATH", pr.Package)
-			err = c.Run()
+		//generating the routes from the OpenAPI document
+		if len(pr.OpenAPI) > 0 {
+			sync, err := project.SyncOpenAPI(pr.Destination, "")
 			if err != nil {
-				//Error while installing the project
-				fmt.Println("Project is generated. But couldn't add go modules to it", err)
+				//Error while generating the routes from the OpenAPI document
+				fmt.Println(err)
 				os.Exit(1)
 			}
+			fmt.Print(sync)
 		}
 
-		//installing the project
-		c := exec.Command("go", "install", pr.Package)
-		c.Dir = pr.Destination
-		c.Stderr = os.Stderr
-		fmt.Println("Installing", pr.Name)
-		err = c.Run()
-		if err != nil {
-			//Error while installing the project
-			fmt.Println("Project is generated. But couldn't install it", err)
+		//running the post generation steps
+		results := pr.RunPostSteps(steps)
+		if !printSteps(results) {
+			fmt.Println("Project is generated in " + pr.Destination + ". But some of the post generation steps failed")
 			os.Exit(1)
 		}
 
@@ -84,7 +146,77 @@ var generateCmd = &cobra.Command{
 	},
 }
 
-func prompts(ui *input.UI) (*project.Project, error) {
+//printSteps prints the summary of the post generation steps. It returns false if any of the steps failed.
+func printSteps(results []project.StepResult) bool {
+	ok := true
+	fmt.Println()
+	fmt.Println("Post generation steps")
+	for _, v := range results {
+		if len(v.Detail) > 0 {
+			fmt.Printf("  %-8s %-7s %s\n", v.Name, v.Status, v.Detail)
+		} else {
+			fmt.Printf("  %-8s %s\n", v.Name, v.Status)
+		}
+		ok = ok && v.Status != project.StepFailed
+	}
+	return ok
+}
+
+//printPlan prints the files, refactors and diff of the project generation without generating it.
+//It returns the conflict error the generation would fail with if any of the files conflict.
+func printPlan(pr *project.Project) error {
+	/*
+	 * We will get the plan of the project
+	 * Then print the files with their refactors
+	 * Then print the diff of the rendered files
+	 * Then we will return the conflicts if any
+	 */
+	plan, err := pr.Plan()
+	if err != nil {
+		return err
+	}
+	fmt.Println("Dry run. Nothing will be written to", plan.Destination)
+	conflicts := []string{}
+	for _, v := range plan.Files {
+		action := "create"
+		if v.Exists {
+			action = "overwrite"
+		}
+		if v.Kept {
+			action = "keep"
+		}
+		if v.Conflict {
+			action = "conflict"
+			conflicts = append(conflicts, v.Path)
+		}
+		fmt.Println(action, v.Path)
+		for _, r := range v.Refactors {
+			if len(r.Find) == 0 {
+				fmt.Println("  refactor", r.Name)
+				continue
+			}
+			matches := "matches"
+			if r.Matches == 1 {
+				matches = "match"
+			}
+			fmt.Println("  refactor", r.Name, "-", r.Matches, matches)
+		}
+	}
+	fmt.Println()
+	for _, v := range plan.Files {
+		fmt.Print(v.Diff)
+	}
+
+	//returning the conflicts
+	if len(conflicts) > 0 {
+		return project.ConflictError{Files: conflicts}
+	}
+	return nil
+}
+
+//prompts will ask the user for the project details that are missing in the given project.
+//If assumeYes is true, the defaults are taken without reading the input.
+func prompts(ui *input.UI, pr *project.Project, assumeYes bool) error {
 	/*
 	 * First we will ask for the name of the project
 	 * Then for project description
@@ -92,119 +224,146 @@ func prompts(ui *input.UI) (*project.Project, error) {
 	 * Then for project destination
 	 * Then for project package name
 	 * then for license
+	 * Then for the optional components
 	 */
-	name, err := ui.Ask("Project name", &input.Options{
-		Default:  "Web Server",
-		Required: true,
-	})
+	err := ask(ui, &pr.Name, "Project name", "Web Server", assumeYes, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	description, err := ui.Ask("Project description", &input.Options{
-		Default:  "Backend server",
-		Required: true,
-	})
+	err = ask(ui, &pr.Description, "Project description", "Backend server", assumeYes, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	author, err := promptAuthor(ui)
+	err = promptAuthor(ui, &pr.Author, assumeYes)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	dst, err := ui.Ask("Project destination", &input.Options{
-		Default:  project.GoPath() + "github.com" + project.Separator + strings.Split(author.Email, "@")[0] + project.Separator + "web-server",
-		Required: true,
-	})
+	user := strings.Split(pr.Author.Email, "@")[0]
+	err = ask(ui, &pr.Destination, "Project destination",
+		project.GoPath()+"github.com"+project.Separator+user+project.Separator+"web-server", assumeYes, project.ValidateDestination)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	//if destination is not absolute, then add absolute path to it
-	if strings.Index(dst, project.Separator) != 0 {
+	if !filepath.IsAbs(pr.Destination) {
 		dir, err := os.Getwd()
 		if err != nil {
-			return nil, err
+			return err
 		}
-		dst = dir + project.Separator + dst
+		pr.Destination = dir + project.Separator + pr.Destination
 	}
-	pkgName, err := ui.Ask("Package name", &input.Options{
-		Default:  "github.com/" + strings.Split(author.Email, "@")[0] + "/web-server",
-		Required: true,
-	})
+	err = ask(ui, &pr.Package, "Package name", "github.com/"+user+"/web-server", assumeYes, project.ValidatePackage)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	lic, err := promptLicense(ui)
+	licenses, err := pr.Licenses()
 	if err != nil {
-		return nil, err
+		return err
 	}
-
-	return &project.Project{
-		Name:        name,
-		Description: description,
-		Author:      author,
-		Destination: dst,
-		Package:     pkgName,
-		License:     lic,
-	}, nil
+	err = promptLicense(ui, &pr.License, licenses, assumeYes)
+	if err != nil {
+		return err
+	}
+	return promptComponents(ui, pr, assumeYes)
 }
 
-func promptAuthor(ui *input.UI) (project.Author, error) {
+func promptAuthor(ui *input.UI, author *project.Author, assumeYes bool) error {
 	/*
 	 * First we will ask for the author name
 	 * Then for author email
 	 */
-	name, err := ui.Ask("Author name", &input.Options{
-		Default:  "cuttle.ai",
-		Required: true,
-	})
-	if err != nil {
-		return project.Author{}, err
-	}
-	email, err := ui.Ask("Author email", &input.Options{
-		Default:  "hi@cuttle.ai",
-		Required: true,
-	})
+	err := ask(ui, &author.Name, "Author name", "cuttle.ai", assumeYes, nil)
 	if err != nil {
-		return project.Author{}, err
+		return err
 	}
-	return project.Author{Name: name, Email: email}, nil
+	return ask(ui, &author.Email, "Author email", "hi@cuttle.ai", assumeYes, project.ValidateEmail)
 }
 
-func promptLicense(ui *input.UI) (project.License, error) {
+func promptLicense(ui *input.UI, lic *project.License, licenses []project.LicenseType, assumeYes bool) error {
 	/*
 	 * First we will ask for the license type
 	 * Then for copyright year
 	 * Then for organisation
 	 */
-	licType, err := ui.Select("Type of license", []string{
-		string(project.AGPL3),
-		string(project.BSD2),
-		string(project.BSD3),
-		string(project.CLOSED),
-		string(project.GPL2),
-		string(project.MIT),
-		string(project.UNLICENSED),
-	}, &input.Options{
-		Default:  string(project.MIT),
-		Required: true,
-	})
-	if err != nil {
-		return project.License{}, err
+	if len(lic.Type) == 0 && len(licenses) > 0 {
+		types := []string{}
+		for _, v := range licenses {
+			types = append(types, string(v))
+		}
+		//MIT is the default if available
+		licType := types[0]
+		for _, v := range types {
+			if v == string(project.MIT) {
+				licType = v
+			}
+		}
+		var err error
+		if !assumeYes {
+			licType, err = ui.Select("Type of license", types, &input.Options{
+				Default:  licType,
+				Required: true,
+			})
+		}
+		if err != nil {
+			return err
+		}
+		lic.Type = project.LicenseType(licType)
 	}
-	year, err := ui.Ask("Copyright year", &input.Options{
-		Default:  strconv.Itoa(time.Now().Year()),
-		Required: true,
-	})
+	err := ask(ui, &lic.Year, "Copyright year", strconv.Itoa(time.Now().Year()), assumeYes, project.ValidateYear)
 	if err != nil {
-		return project.License{}, err
+		return err
 	}
+	return ask(ui, &lic.Organisation, "Organisation", "Cuttle.ai", assumeYes, nil)
+}
 
-	org, err := ui.Ask("Organisation", &input.Options{
-		Default:  "Cuttle.ai",
-		Required: true,
+//promptComponents will ask the user whether to include each of the optional components if they are not chosen already.
+//If assumeYes is true, all of them are included.
+func promptComponents(ui *input.UI, pr *project.Project, assumeYes bool) error {
+	if pr.Components != nil {
+		return nil
+	}
+	pr.Components = project.AllComponents()
+	if assumeYes {
+		return nil
+	}
+	for _, v := range project.ComponentNames {
+		ans, err := ui.Select("Include "+v, []string{"yes", "no"}, &input.Options{
+			Default:  "yes",
+			Required: true,
+		})
+		if err != nil {
+			return err
+		}
+		*pr.Components.Chosen(v) = ans == "yes"
+	}
+	return nil
+}
+
+//ask will ask the user for the value only if the given value is empty.
+//If assumeYes is true, the default is taken without reading the input.
+//If validate is given, the user is asked again till the value is valid.
+func ask(ui *input.UI, value *string, query, def string, assumeYes bool, validate input.ValidateFunc) error {
+	/*
+	 * If the value is already there, we don't have to ask
+	 * If we can assume yes, then take the default
+	 * Else ask the user
+	 */
+	if len(*value) != 0 {
+		return nil
+	}
+	if assumeYes {
+		*value = def
+		return nil
+	}
+	v, err := ui.Ask(query, &input.Options{
+		Default:      def,
+		Required:     true,
+		Loop:         validate != nil,
+		ValidateFunc: validate,
 	})
 	if err != nil {
-		return project.License{}, err
+		return err
 	}
-	return project.License{Type: project.LicenseType(licType), Year: year, Organisation: org}, nil
+	*value = v
+	return nil
 }
@@ -0,0 +1,81 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package deps
+
+import (
+	"strings"
+)
+
+/*
+ * This file contains the compatibility rules between the license of a project and the licenses of its dependencies
+ */
+
+//family is a family of licenses with similar obligations
+type family int
+
+const (
+	//proprietary licenses don't allow redistribution of the source code
+	proprietary family = iota
+	//permissive licenses only ask for attribution
+	permissive
+	//weakCopyleft licenses ask for the changes to the licensed files or library to be shared
+	weakCopyleft
+	//strongCopyleft licenses ask for the whole program to be shared under the same license
+	strongCopyleft
+	//networkCopyleft licenses ask for the whole program to be shared even when it is only served over a network
+	networkCopyleft
+)
+
+//families has the family of the known licenses. The licenses not in it are taken as proprietary
+var families = map[string]family{
+	"MIT":          permissive,
+	"ISC":          permissive,
+	"BSD-2-Clause": permissive,
+	"BSD-3-Clause": permissive,
+	"Apache-2.0":   permissive,
+	"Unlicense":    permissive,
+	"MPL-2.0":      weakCopyleft,
+	"LGPL-2.1":     weakCopyleft,
+	"LGPL-3.0":     weakCopyleft,
+	"GPL-2.0":      strongCopyleft,
+	"GPL-3.0":      strongCopyleft,
+	"AGPL-3.0":     networkCopyleft,
+}
+
+//base returns the license without the -only or -or-later suffix of the SPDX identifier
+func base(license string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(license, "-only"), "-or-later")
+}
+
+//Compatible tells whether a project with the given license can use a dependency with the given license.
+//Both are SPDX identifiers. Licenses which aren't known, like LicenseRef-CLOSED, are taken as proprietary.
+//If they are not compatible, the reason is returned.
+func Compatible(project, dependency string) (bool, string) {
+	/*
+	 * Copyleft dependencies need the project to be under a compatible copyleft license
+	 * Apart from that GPL-2.0 can't be combined with Apache-2.0 and the version 3 licenses
+	 * Since go links statically, LGPL dependencies can't be used in proprietary projects as it is
+	 */
+	p, d := base(project), base(dependency)
+	pf, ok := families[p]
+	if !ok {
+		pf = proprietary
+	}
+	df := families[d]
+
+	switch {
+	case df == networkCopyleft && p != "AGPL-3.0" && p != "GPL-3.0":
+		return false, d + " needs the project to be shared under the AGPL-3.0 even when it is only served over a network"
+	case df == strongCopyleft && pf < strongCopyleft:
+		return false, d + " needs the whole project to be shared under the " + d
+	case p == "GPL-2.0" && (d == "Apache-2.0" || d == "GPL-3.0" || d == "LGPL-3.0" || d == "AGPL-3.0"):
+		return false, d + " is not compatible with the GPL-2.0 of the project"
+	case d == "GPL-2.0" && pf >= strongCopyleft && p != "GPL-2.0":
+		return false, "GPL-2.0 is not compatible with the " + p + " of the project unless the module allows later versions"
+	case strings.HasPrefix(d, "LGPL") && pf == proprietary:
+		return false, "go links the modules statically. So the " + d + " needs the users to be able to relink the project with their own version of the module"
+	}
+	return true, ""
+}
@@ -0,0 +1,57 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package deps_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/deps"
+)
+
+/*
+ * This file contains the tests for the source code of compat.go
+ */
+
+var compatibletcs = []struct {
+	Name       string
+	Project    string
+	Dependency string
+	Expected   bool
+}{
+	{"Closed project with MIT", "LicenseRef-CLOSED", "MIT", true},
+	{"Closed project with AGPL", "LicenseRef-CLOSED", "AGPL-3.0", false},
+	{"Closed project with GPL", "LicenseRef-CLOSED", "GPL-2.0", false},
+	{"Closed project with LGPL", "LicenseRef-CLOSED", "LGPL-3.0", false},
+	{"Closed project with MPL", "LicenseRef-CLOSED", "MPL-2.0", true},
+	{"MIT project with Apache", "MIT", "Apache-2.0", true},
+	{"MIT project with GPL", "MIT", "GPL-3.0", false},
+	{"MIT project with LGPL", "MIT", "LGPL-3.0", true},
+	{"GPL-3 project with Apache", "GPL-3.0-only", "Apache-2.0", true},
+	{"GPL-3 project with AGPL", "GPL-3.0-only", "AGPL-3.0", true},
+	{"GPL-3 project with GPL-2", "GPL-3.0-only", "GPL-2.0", false},
+	{"GPL-2 project with Apache", "GPL-2.0-only", "Apache-2.0", false},
+	{"GPL-2 project with GPL-3", "GPL-2.0-only", "GPL-3.0", false},
+	{"GPL-2 project with BSD", "GPL-2.0-only", "BSD-3-Clause", true},
+	{"AGPL project with GPL-3", "AGPL-3.0-only", "GPL-3.0", true},
+	{"LGPL project with GPL", "LGPL-3.0-only", "GPL-3.0", false},
+	{"MPL project with AGPL", "MPL-2.0", "AGPL-3.0", false},
+}
+
+//TestCompatible is the test suite for the compatibility of the licenses
+func TestCompatible(t *testing.T) {
+	for _, v := range compatibletcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			ok, reason := deps.Compatible(v.Project, v.Dependency)
+			if ok != v.Expected {
+				t.Error("Expected", v.Expected, "Got", ok, reason)
+			}
+			if !ok && len(reason) == 0 {
+				t.Error("Expected a reason for the conflict")
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//Package deps reports the licenses of the dependencies of a generated project.
+//The dependencies are listed from the go.mod of the project and their licenses are detected
+//from the license files of the modules in the module cache.
+package deps
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+/*
+ * This file contains the definitions of the dependency license report
+ */
+
+//Status is the status of the license of a dependency with respect to the license of the project
+type Status string
+
+const (
+	//OK is a dependency whose license is compatible with the license of the project
+	OK Status = "ok"
+	//Conflict is a dependency whose license conflicts with the license of the project
+	Conflict Status = "conflict"
+	//Review is a dependency whose license couldn't be detected or needs a manual review
+	Review Status = "review"
+)
+
+//Unknown is the license of the dependencies whose license couldn't be detected
+const Unknown = "NOASSERTION"
+
+//Dependency is a module required by the project
+type Dependency struct {
+	//Path of the module
+	Path string `json:"path"`
+	//Version of the module
+	Version string `json:"version"`
+	//Indirect tells whether the module is an indirect dependency of the project
+	Indirect bool `json:"indirect"`
+	//License is the SPDX identifier of the license detected for the module
+	License string `json:"license"`
+	//LicenseFile is the license file of the module from which the license was detected
+	LicenseFile string `json:"licenseFile,omitempty"`
+	//Status of the license with respect to the license of the project
+	Status Status `json:"status"`
+	//Note explains the status
+	Note string `json:"note,omitempty"`
+}
+
+//Report is the license report of a project
+type Report struct {
+	//Module is the module path of the project
+	Module string `json:"module"`
+	//License is the SPDX identifier of the license of the project
+	License string `json:"license"`
+	//Dependencies are the modules required by the project
+	Dependencies []Dependency `json:"dependencies"`
+}
+
+//Conflicts returns the dependencies whose license conflicts with the license of the project
+func (r Report) Conflicts() []Dependency {
+	deps := []Dependency{}
+	for _, v := range r.Dependencies {
+		if v.Status == Conflict {
+			deps = append(deps, v)
+		}
+	}
+	return deps
+}
+
+//module is a module as listed by go list -m -json
+type module struct {
+	Path     string
+	Version  string
+	Main     bool
+	Indirect bool
+	Dir      string
+	Replace  *module
+	Error    *struct{ Err string }
+}
+
+//Inspect lists the modules required by the go.mod of the project in the given directory and detects
+//their licenses from the module cache. The license of each module is checked against the given license of the project.
+func Inspect(dir, license string) (*Report, error) {
+	/*
+	 * We will list the modules of the project
+	 * Then we will detect the license of each module
+	 * Then we will check it against the license of the project
+	 */
+	//listing the modules
+	modules, err := listModules(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	//detecting the licenses
+	r := &Report{License: license, Dependencies: []Dependency{}}
+	for _, m := range modules {
+		if m.Main {
+			r.Module = m.Path
+			continue
+		}
+		d := Dependency{Path: m.Path, Version: m.Version, Indirect: m.Indirect, License: Unknown}
+		modDir := m.Dir
+		if m.Replace != nil {
+			d.Version = m.Replace.Version
+			if len(modDir) == 0 {
+				modDir = m.Replace.Dir
+			}
+		}
+		switch {
+		case len(modDir) == 0 && m.Error != nil:
+			d.Note = m.Error.Err
+		case len(modDir) == 0:
+			d.Note = "the module is not in the module cache. Run go mod download"
+		default:
+			d.License, d.LicenseFile, err = DetectDir(modDir)
+			if err != nil {
+				//error while detecting the license of the module
+				fmt.Println("Error while detecting the license of the module", m.Path)
+				return nil, err
+			}
+		}
+		d.Status, d.Note = check(license, d)
+		r.Dependencies = append(r.Dependencies, d)
+	}
+	sort.Slice(r.Dependencies, func(i, j int) bool { return r.Dependencies[i].Path < r.Dependencies[j].Path })
+	return r, nil
+}
+
+//check returns the status of the license of the dependency with respect to the license of the project
+func check(license string, d Dependency) (Status, string) {
+	if d.License == Unknown {
+		if len(d.Note) == 0 {
+			return Review, "couldn't detect the license of the module"
+		}
+		return Review, d.Note
+	}
+	if ok, reason := Compatible(license, d.License); !ok {
+		return Conflict, reason
+	}
+	return OK, ""
+}
+
+//listModules lists the modules of the project in the given directory using go list
+func listModules(dir string) ([]module, error) {
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err != nil {
+		return nil, fmt.Errorf("couldn't find the go.mod of the project in %s: %v", dir, err)
+	}
+	c := exec.Command("go", "list", "-m", "-e", "-json", "all")
+	c.Dir = dir
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	c.Stdout, c.Stderr = out, errOut
+	err := c.Run()
+	if err != nil {
+		return nil, fmt.Errorf("go list -m all: %v %s", err, strings.TrimSpace(errOut.String()))
+	}
+	modules := []module{}
+	dec := json.NewDecoder(out)
+	for {
+		m := module{}
+		err := dec.Decode(&m)
+		if err == io.EOF {
+			return modules, nil
+		}
+		if err != nil {
+			//error while decoding the modules listed
+			fmt.Println("Error while decoding the modules listed by go list")
+			return nil, err
+		}
+		modules = append(modules, m)
+	}
+}
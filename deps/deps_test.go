@@ -0,0 +1,82 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package deps_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/deps"
+)
+
+/*
+ * This file contains the tests for the source code of deps.go
+ */
+
+//writeModule writes a module with the given go.mod and license in the given directory
+func writeModule(t *testing.T, dir, gomod, license string) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(license) == 0 {
+		return
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "LICENSE"), []byte(license), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+//TestInspect inspects a closed project requiring MIT, AGPL and unlicensed modules replaced by local directories
+func TestInspect(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not available")
+	}
+	dir := t.TempDir()
+	writeModule(t, filepath.Join(dir, "app"), `module example.com/app
+
+go 1.16
+
+require (
+	example.com/agpl v1.0.0
+	example.com/mit v1.0.0
+	example.com/none v1.0.0
+)
+
+replace example.com/agpl => ../agpl
+
+replace example.com/mit => ../mit
+
+replace example.com/none => ../none
+`, "")
+	writeModule(t, filepath.Join(dir, "agpl"), "module example.com/agpl\n", "GNU AFFERO GENERAL PUBLIC LICENSE\nVersion 3, 19 November 2007")
+	writeModule(t, filepath.Join(dir, "mit"), "module example.com/mit\n", "Permission is hereby granted, free of charge, to any person")
+	writeModule(t, filepath.Join(dir, "none"), "module example.com/none\n", "")
+
+	report, err := deps.Inspect(filepath.Join(dir, "app"), "LicenseRef-CLOSED")
+	if err != nil {
+		t.Fatal("Error while inspecting the project", err)
+	}
+	expected := map[string]deps.Status{"example.com/agpl": deps.Conflict, "example.com/mit": deps.OK, "example.com/none": deps.Review}
+	if report.Module != "example.com/app" || len(report.Dependencies) != len(expected) {
+		t.Fatal("Expected the dependencies of example.com/app. Got", report)
+	}
+	for _, v := range report.Dependencies {
+		if v.Status != expected[v.Path] {
+			t.Error("Expected", v.Path, "to be", expected[v.Path], "Got", v.Status, v.License, v.Note)
+		}
+	}
+	if len(report.Conflicts()) != 1 {
+		t.Error("Expected one conflict. Got", report.Conflicts())
+	}
+}
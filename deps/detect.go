@@ -0,0 +1,97 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package deps
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+/*
+ * This file contains the detection of the licenses from the license texts
+ */
+
+//licenseFile matches the names of the license files in a module
+var licenseFile = regexp.MustCompile(`(?i)^(licen[cs]e|copying|unlicense)(\.(txt|md|markdown|rst))?$`)
+
+//space matches the runs of white space in a license text
+var space = regexp.MustCompile(`\s+`)
+
+//head is the number of characters at the start of a license text having its title
+const head = 500
+
+//signature identifies a license from the phrases in its text
+type signature struct {
+	//License is the SPDX identifier of the license
+	License string
+	//Title indicates that the phrases are looked for only in the title at the start of the text.
+	//It is required for the licenses whose texts mention other licenses
+	Title bool
+	//Phrases are the lower case phrases all of which have to be present in the license text
+	Phrases []string
+}
+
+//signatures are the signatures of the known licenses. The more specific ones come first
+var signatures = []signature{
+	{"AGPL-3.0", true, []string{"gnu affero general public license", "version 3"}},
+	{"LGPL-3.0", true, []string{"gnu lesser general public license", "version 3"}},
+	{"LGPL-2.1", true, []string{"gnu lesser general public license", "version 2.1"}},
+	{"GPL-3.0", true, []string{"gnu general public license", "version 3"}},
+	{"GPL-2.0", true, []string{"gnu general public license", "version 2"}},
+	{"MPL-2.0", true, []string{"mozilla public license", "2.0"}},
+	{"Apache-2.0", true, []string{"apache license", "version 2.0"}},
+	{"Unlicense", false, []string{"this is free and unencumbered software released into the public domain"}},
+	{"BSD-3-Clause", false, []string{"redistribution and use in source and binary forms", "provided that the following conditions are met", "neither the name"}},
+	{"BSD-3-Clause", false, []string{"redistribution and use in source and binary forms", "provided that the following conditions are met", "names of its contributors may be used"}},
+	{"BSD-2-Clause", false, []string{"redistribution and use in source and binary forms", "provided that the following conditions are met"}},
+	{"ISC", false, []string{"permission to use, copy, modify, and/or distribute this software for any purpose"}},
+	{"MIT", false, []string{"permission is hereby granted, free of charge"}},
+}
+
+//Detect returns the SPDX identifier of the license in the given text. It returns Unknown if the license isn't known.
+func Detect(text string) string {
+	text = space.ReplaceAllString(strings.ToLower(strings.TrimSpace(text)), " ")
+	title := text
+	if len(title) > head {
+		title = title[:head]
+	}
+	for _, s := range signatures {
+		in, found := text, true
+		if s.Title {
+			in = title
+		}
+		for _, p := range s.Phrases {
+			found = found && strings.Contains(in, p)
+		}
+		if found {
+			return s.License
+		}
+	}
+	return Unknown
+}
+
+//DetectDir detects the license of the module in the given directory from the license file at its root.
+//It returns the license and the license file. The license is Unknown if the module doesn't have a license file.
+func DetectDir(dir string) (string, string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return Unknown, "", err
+	}
+	for _, f := range files {
+		if f.IsDir() || !licenseFile.MatchString(f.Name()) {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return Unknown, "", err
+		}
+		if l := Detect(string(b)); l != Unknown {
+			return l, f.Name(), nil
+		}
+	}
+	return Unknown, "", nil
+}
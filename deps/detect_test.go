@@ -0,0 +1,69 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package deps_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/deps"
+)
+
+/*
+ * This file contains the tests for the source code of detect.go
+ */
+
+var detecttcs = []struct {
+	Name     string
+	Template string
+	Expected string
+}{
+	{"AGPL-3", "AGPL-3", "AGPL-3.0"},
+	{"Apache-2", "Apache-2", "Apache-2.0"},
+	{"BSD-2", "BSD-2", "BSD-2-Clause"},
+	{"BSD-3", "BSD-3", "BSD-3-Clause"},
+	{"CLOSED", "CLOSED", deps.Unknown},
+	{"GPL-2", "GPL-2", "GPL-2.0"},
+	{"GPL-3", "GPL-3", "GPL-3.0"},
+	{"LGPL-3", "LGPL-3", "LGPL-3.0"},
+	{"MIT", "MIT", "MIT"},
+	{"MPL-2", "MPL-2", "MPL-2.0"},
+	{"UNLICENSED", "UNLICENSED", "Unlicense"},
+}
+
+//TestDetect detects the licenses from the license templates of web-starter
+func TestDetect(t *testing.T) {
+	for _, v := range detecttcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			b, err := ioutil.ReadFile(filepath.Join("..", "licenses", v.Template, "LICENSE"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := deps.Detect(string(b)); got != v.Expected {
+				t.Error("Expected", v.Expected, "Got", got)
+			}
+		})
+	}
+}
+
+//TestDetectDir detects the license from the license file of a module
+func TestDetectDir(t *testing.T) {
+	dir := t.TempDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "LICENSE.md"), []byte("ISC License\n\nPermission to use, copy, modify, and/or\ndistribute this software for any purpose"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, f, err := deps.DetectDir(dir)
+	if err != nil || l != "ISC" || f != "LICENSE.md" {
+		t.Error("Expected ISC from LICENSE.md. Got", l, f, err)
+	}
+	l, _, err = deps.DetectDir(t.TempDir())
+	if err != nil || l != deps.Unknown {
+		t.Error("Expected", deps.Unknown, "for a module without license. Got", l, err)
+	}
+}
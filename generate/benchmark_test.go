@@ -0,0 +1,89 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package generate_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/cuttle-ai/web-starter/generate"
+)
+
+/*
+ * This file contains the benchmarks of generating the sources of a large template
+ */
+
+//benchFiles is the number of go files in the large template
+const benchFiles = 200
+
+//largeTemplate returns a template having many go files with imports, comments, string literals and placeholders
+//along with the sources generating them
+func largeTemplate() []generate.Source {
+	templates := fstest.MapFS{}
+	sources := []generate.Source{}
+	for i := 0; i < benchFiles; i++ {
+		b := &strings.Builder{}
+		fmt.Fprintf(b, "// Copyright 2019 Cuttle.ai. All rights reserved.\n\n//Package pkg%d is part of {{.Name}}\npackage pkg%d\n\n", i, i)
+		b.WriteString("import (\n\t\"fmt\"\n\n\t\"github.com/cuttle-ai/web-starter/boilerplate/config\"\n\t\"github.com/cuttle-ai/web-starter/boilerplate/log\"\n)\n\n")
+		for j := 0; j < 50; j++ {
+			fmt.Fprintf(b, "//Handler%d handles the route of web-server\nfunc Handler%d(c config.Config) {\n\tlog.Info(fmt.Sprint(\"web-server\", %d))\n}\n\n", j, j, j)
+		}
+		name := fmt.Sprintf("file%d.go", i)
+		templates["boilerplate/"+name] = &fstest.MapFile{Data: []byte(b.String())}
+		sources = append(sources, generate.Source{
+			FS:                  templates,
+			Path:                "boilerplate",
+			FileName:            name,
+			RelativeDestination: fmt.Sprintf("pkg%d", i),
+			Refactors: []generate.Refactor{
+				{Name: "template", Source: generate.NewTemplateRefactor(map[string]string{"Name": "Orders"})},
+				{Name: "package", Find: "github.com/cuttle-ai/web-starter/boilerplate", Replace: "github.com/jane/orders", Source: generate.NewPackageRefactor()},
+				{Name: "comment", Find: "web-server", Replace: "orders", Source: generate.NewCommentRefactor()},
+				{Name: "string", Find: "web-server", Replace: "orders", Source: generate.NewStringLiteralRefactor()},
+				{Name: "header", Source: generate.NewHeaderRefactor("Copyright 2019 Jane\n\nSPDX-License-Identifier: MIT")},
+			},
+		})
+	}
+	return sources
+}
+
+//BenchmarkGenerate generates the large template reading, refactoring and writing each file once
+func BenchmarkGenerate(b *testing.B) {
+	sources := largeTemplate()
+	dst := b.TempDir()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range sources {
+			err := sources[i].Generate(dst)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+//BenchmarkGeneratePerRefactor generates the large template copying each file and then reading,
+//parsing and writing it again for every refactor as it was done before the single pass
+func BenchmarkGeneratePerRefactor(b *testing.B) {
+	sources := largeTemplate()
+	dst := b.TempDir()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range sources {
+			file, err := sources[i].Copy(dst)
+			if err != nil {
+				b.Fatal(err)
+			}
+			for j := range sources[i].Refactors {
+				err = sources[i].Refactors[j].Do(file)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	}
+}
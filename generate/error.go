@@ -0,0 +1,32 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package generate
+
+/*
+ * This file has the definition of the error returned while generating a source
+ */
+
+//Error is the error returned when generating a source fails. It names the source and the refactor that failed
+type Error struct {
+	//Source is the name of the source that failed
+	Source string
+	//Refactor is the name of the refactor that failed. It is empty if the source failed before refactoring
+	Refactor string
+	//Err is the underlying error
+	Err error
+}
+
+//Error is the error implementation of the generation error
+func (e *Error) Error() string {
+	if len(e.Refactor) == 0 {
+		return "generating the source " + e.Source + " failed: " + e.Err.Error()
+	}
+	return "generating the source " + e.Source + " failed in the refactor " + e.Refactor + ": " + e.Err.Error()
+}
+
+//Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
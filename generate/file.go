@@ -0,0 +1,125 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"io/ioutil"
+	"os"
+)
+
+/*
+ * This file has the definition of the in memory file refactored by the refactor sources
+ */
+
+//File is a source file loaded in memory. The refactors of a source are applied to it one after the other and
+//it is written only once at the end. The go source is parsed only when a refactor asks for its syntax tree and the
+//tree is shared by the refactors after it till a refactor changes the content as text.
+type File struct {
+	//Name is the name of the file used in the errors and as the name of the template
+	Name string
+	//Mode is the permission with which the file is written. Defaults to 0644
+	Mode fs.FileMode
+	//content is the content of the file. It is stale if the syntax tree was given out after it was formatted
+	content []byte
+	//fset has the positions of the syntax tree
+	fset *token.FileSet
+	//tree is the parsed syntax tree of the go source. It is nil till it is asked for
+	tree *ast.File
+	//dirty indicates that the syntax tree may have changed after the content was formatted from it
+	dirty bool
+}
+
+//NewFile returns the in memory file with the given name and content
+func NewFile(name string, content []byte) *File {
+	return &File{Name: name, content: content}
+}
+
+//ReadFile reads the file with the given name into memory
+func ReadFile(name string) (*File, error) {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		//error while reading the file
+		fmt.Println("Error while reading the file", name)
+		return nil, err
+	}
+	return NewFile(name, b), nil
+}
+
+//Bytes returns the content of the file. If the syntax tree of the file was asked for, it is formatted into the content.
+func (f *File) Bytes() ([]byte, error) {
+	if !f.dirty {
+		return f.content, nil
+	}
+	buf := &bytes.Buffer{}
+	err := format.Node(buf, f.fset, f.tree)
+	if err != nil {
+		//error while formatting the syntax tree
+		fmt.Println("Error while formatting the go source", f.Name)
+		return nil, err
+	}
+	f.content, f.dirty = buf.Bytes(), false
+	return f.content, nil
+}
+
+//SetBytes replaces the content of the file. The syntax tree is parsed again when it is asked for next.
+func (f *File) SetBytes(content []byte) {
+	f.content, f.fset, f.tree, f.dirty = content, nil, nil, false
+}
+
+//AST returns the syntax tree of the go source file with its comments along with the file set having its positions.
+//Changes made to the tree are part of the content of the file.
+func (f *File) AST() (*token.FileSet, *ast.File, error) {
+	if f.tree == nil {
+		fset := token.NewFileSet()
+		tree, err := parser.ParseFile(fset, f.Name, f.content, parser.ParseComments)
+		if err != nil {
+			//error while parsing the source file
+			fmt.Println("Error while parsing the go source file", f.Name)
+			return nil, nil, err
+		}
+		f.fset, f.tree = fset, tree
+	}
+	f.dirty = true
+	return f.fset, f.tree, nil
+}
+
+//Write writes the content of the file to the file with the given name with the permission of the file
+func (f *File) Write(name string) error {
+	b, err := f.Bytes()
+	if err != nil {
+		return err
+	}
+	mode := f.Mode
+	if mode == 0 {
+		mode = 0644
+	}
+	err = ioutil.WriteFile(name, b, mode)
+	if err == nil {
+		//the permission is given only to the new files while writing. So setting it for the existing ones
+		err = os.Chmod(name, mode)
+	}
+	if err != nil {
+		//error while writing the file
+		fmt.Println("Error while writing the file", name)
+	}
+	return err
+}
+
+//IsBinary tells whether the content of the file is binary. Like git, a file having a NUL byte
+//in its first 8000 bytes is considered binary.
+func (f *File) IsBinary() bool {
+	b := f.content
+	if len(b) > 8000 {
+		b = b[:8000]
+	}
+	return bytes.IndexByte(b, 0) >= 0
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package generate
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+/*
+ * This file has the defintions of the header refactor struct which implements the RefactorSource.
+ */
+
+//HeaderRefactor replaces the license header comment at the top of a go source file with the given header.
+//The license header is the first comment of the file before the package clause, which isn't the package
+//documentation and mentions a copyright or a license. If the file doesn't have one, the header is added at the top.
+type HeaderRefactor struct {
+	//Header is the license header without the comment markers. Each line of it becomes a line comment
+	Header string
+}
+
+//NewHeaderRefactor is the constructor for the header refactor
+func NewHeaderRefactor(header string) HeaderRefactor {
+	return HeaderRefactor{Header: header}
+}
+
+//Apply will replace the license header of the given go source file.
+//Since the whole header is replaced at once, the replace func is not called.
+//So the find and replace of the refactor are not used.
+func (h HeaderRefactor) Apply(ctx context.Context, file *File, replace func(string) (string, error)) error {
+	/*
+	 * We will parse the comments of the source file
+	 * We will find the existing header
+	 * Then we will replace it with the new header or add the new header at the top
+	 */
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	//parsing the source file
+	b, err := file.Bytes()
+	if err != nil {
+		return err
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file.Name, b, parser.ParseComments|parser.PackageClauseOnly)
+	if err != nil {
+		//error while parsing the source file
+		fmt.Println("Error while parsing the go source file", file.Name)
+		return err
+	}
+
+	//replacing the existing header or adding the new one
+	src := string(b)
+	header := h.comment()
+	if g := licenseHeader(f); g != nil {
+		src = src[:fset.Position(g.Pos()).Offset] + header + src[fset.Position(g.End()).Offset:]
+	} else {
+		src = header + "\n\n" + src
+	}
+	file.SetBytes([]byte(src))
+	return nil
+}
+
+//comment returns the header as line comments
+func (h HeaderRefactor) comment() string {
+	lines := strings.Split(strings.TrimRight(h.Header, "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight("// "+l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+//licenseHeader returns the license header comment of the given file. It returns nil if the file doesn't have one.
+func licenseHeader(f *ast.File) *ast.CommentGroup {
+	for _, g := range f.Comments {
+		if g == f.Doc || g.Pos() > f.Package {
+			return nil
+		}
+		text := strings.ToLower(g.Text())
+		if strings.Contains(text, "copyright") || strings.Contains(text, "license") {
+			return g
+		}
+	}
+	return nil
+}
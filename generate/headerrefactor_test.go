@@ -0,0 +1,101 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package generate_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/generate"
+)
+
+/*
+ * This file contains the tests for the source code of headerrefactor.go
+ */
+
+//header is the header used in the header refactor tests
+const header = "Copyright 2020 Acme Inc.\n\nSPDX-License-Identifier: BSD-3-Clause"
+
+//headerComment is the header as comments
+const headerComment = "// Copyright 2020 Acme Inc.\n//\n// SPDX-License-Identifier: BSD-3-Clause"
+
+var headertcs = []struct {
+	Name     string
+	Source   string
+	Expected string
+	Error    bool
+}{
+	{
+		"Replace the existing header",
+		"// Copyright 2019 Cuttle.ai. All rights reserved.\n// Use of this source code is governed by a MIT-style\n// license that can be found in the LICENSE file.\n\n//Package log prints logs\npackage log\n",
+		headerComment + "\n\n//Package log prints logs\npackage log\n",
+		false,
+	},
+	{
+		"Add the header to a file without one",
+		"package log\n\nfunc f() {}\n",
+		headerComment + "\n\npackage log\n\nfunc f() {}\n",
+		false,
+	},
+	{
+		"Package documentation is not a header",
+		"//Package log is licensed to print logs\npackage log\n",
+		headerComment + "\n\n//Package log is licensed to print logs\npackage log\n",
+		false,
+	},
+	{
+		"Header after the build constraint",
+		"//go:build linux\n\n/* Copyright 2019 Cuttle.ai */\n\npackage log\n",
+		"//go:build linux\n\n" + headerComment + "\n\npackage log\n",
+		false,
+	},
+	{
+		"Comments after the package clause are left",
+		"package log\n\n// Copyright is printed by the logger\nfunc f() {}\n",
+		headerComment + "\n\npackage log\n\n// Copyright is printed by the logger\nfunc f() {}\n",
+		false,
+	},
+	{
+		"Invalid go source",
+		"not go",
+		"",
+		true,
+	},
+}
+
+//TestHeaderRefactor is the test suite for the header refactor
+func TestHeaderRefactor(t *testing.T) {
+	for _, v := range headertcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			file := filepath.Join(t.TempDir(), "log.go")
+			err := ioutil.WriteFile(file, []byte(v.Source), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = (&generate.Refactor{Name: "header", Source: generate.NewHeaderRefactor(header)}).Do(file)
+			if err == nil && v.Error {
+				t.Error("Expected an error. Got none.")
+				return
+			}
+			if err != nil && !v.Error {
+				t.Error("Didn't expect an error. Got one", err.Error())
+				return
+			}
+			if v.Error {
+				return
+			}
+			b, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != v.Expected {
+				t.Errorf("Expected\n%s\nGot\n%s", v.Expected, string(b))
+			}
+		})
+	}
+}
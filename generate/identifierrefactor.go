@@ -0,0 +1,118 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package generate
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/*
+ * This file has the defintions of the identifier refactor struct which implements the RefactorSource.
+ */
+
+//majorVersion matches the major version suffix of an import path like v2
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+//IdentifierRefactor is to refactor the identifiers present in a go source code file like the type, function and variable names.
+//The package clause, the names of the imports and the package qualifiers like log in log.Error along with the names selected
+//from the imported packages are left as they are.
+//Since the identifiers are matched by substring, use a regex refactor like ^User$ to rename only the whole identifier.
+type IdentifierRefactor struct{}
+
+//NewIdentifierRefactor is the constructor for the identifier refactor
+func NewIdentifierRefactor() IdentifierRefactor {
+	return IdentifierRefactor{}
+}
+
+//Apply will refactor the identifiers of the go source file provided
+//It will call the replace func with the name of each identifier and rename it with the refactored name.
+//If the refactored name is not a valid identifier, an error is returned.
+//If the replace func returns an error or the context is cancelled, the refactoring is stopped and the error is returned
+func (i IdentifierRefactor) Apply(ctx context.Context, file *File, replace func(string) (string, error)) error {
+	/*
+	 * We will get the syntax tree of the source file
+	 * We will find the identifiers except the package clause, the import names and the package qualifiers
+	 * Will replace the name of each of them
+	 * Will check whether the refactored name is a valid identifier
+	 * The refactored nodes are part of the syntax tree of the file
+	 */
+	//getting the syntax tree of the source file
+	fset, f, err := file.AST()
+	if err != nil {
+		return err
+	}
+
+	//finding the identifiers
+	imports := importNames(f)
+	idents := []*ast.Ident{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch v := n.(type) {
+		case *ast.ImportSpec:
+			return false
+		case *ast.SelectorExpr:
+			//the qualifier doesn't resolve to a declaration in the file if it refers to an imported package
+			if x, ok := v.X.(*ast.Ident); ok && x.Obj == nil && imports[x.Name] {
+				return false
+			}
+		case *ast.Ident:
+			if v != f.Name && v.Name != "_" {
+				idents = append(idents, v)
+			}
+		}
+		return true
+	})
+
+	//replacing the names of the identifiers
+	for _, v := range idents {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		nV, err := replace(v.Name)
+		if err != nil {
+			return err
+		}
+		if !token.IsIdentifier(nV) {
+			//the refactored name is not a valid identifier
+			fmt.Println("The refactored name", nV, "of the identifier", v.Name, "is not a valid identifier in", file.Name)
+			return fmt.Errorf("%q is not a valid identifier to rename %s at %s", nV, v.Name, fset.Position(v.Pos()))
+		}
+		v.Name = nV
+	}
+	return nil
+}
+
+//importNames returns the names by which the packages imported in the go source file are referred to.
+//The name of a package imported without an alias is taken from its path like yaml for gopkg.in/yaml.v3,
+//input for github.com/tcnksm/go-input and gorm for github.com/jinzhu/gorm/v2.
+func importNames(f *ast.File) map[string]bool {
+	names := map[string]bool{}
+	for _, v := range f.Imports {
+		if v.Name != nil {
+			names[v.Name.Name] = true
+			continue
+		}
+		path, err := strconv.Unquote(v.Path.Value)
+		if err != nil {
+			continue
+		}
+		parts := strings.Split(path, "/")
+		name := parts[len(parts)-1]
+		if len(parts) > 1 && majorVersion.MatchString(name) {
+			name = parts[len(parts)-2]
+		}
+		name = strings.TrimPrefix(name, "go-")
+		if i := strings.IndexAny(name, ".-"); i > 0 {
+			name = name[:i]
+		}
+		names[name] = true
+	}
+	return names
+}
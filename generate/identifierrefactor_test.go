@@ -0,0 +1,124 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package generate_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/generate"
+)
+
+/*
+ * This file contains the tests for the source code of identifierrefactor.go
+ */
+
+var identifiertcs = []struct {
+	Name     string
+	Source   string
+	Refactor generate.Refactor
+	Expected string
+	Matches  int
+	Error    bool
+}{
+	{
+		"Type is renamed",
+		"package user\n\n// User is left in the comments\ntype User struct{ Name string }\n\nfunc NewUser() *User { return &User{Name: \"User\"} }\n",
+		generate.Refactor{Find: "User", Replace: "Order"},
+		"package user\n\n// User is left in the comments\ntype Order struct{ Name string }\n\nfunc NewOrder() *Order { return &Order{Name: \"User\"} }\n",
+		4,
+		false,
+	},
+	{
+		"Whole identifiers with regex",
+		"package user\n\ntype User struct{}\n\ntype UserID int\n",
+		generate.Refactor{Find: "^User$", Replace: "Order", IsRegex: true},
+		"package user\n\ntype Order struct{}\n\ntype UserID int\n",
+		1,
+		false,
+	},
+	{
+		"Package clause and imports are left",
+		"package user\n\nimport user \"fmt\"\n\nvar User = user.Sprint\n",
+		generate.Refactor{Find: "^User$", Replace: "Order", IsRegex: true},
+		"package user\n\nimport user \"fmt\"\n\nvar Order = user.Sprint\n",
+		1,
+		false,
+	},
+	{
+		"Package qualifiers are left",
+		"package user\n\nimport \"log\"\n\nfunc logUser(log2 int) { log.Println(log2) }\n",
+		generate.Refactor{Find: "log", Replace: "trace"},
+		"package user\n\nimport \"log\"\n\nfunc traceUser(trace2 int) { log.Println(trace2) }\n",
+		3,
+		false,
+	},
+	{
+		"Package qualifiers of versioned and aliased imports are left",
+		"package user\n\nimport (\n\t\"gopkg.in/yaml.v3\"\n\n\tinput \"github.com/tcnksm/go-input\"\n)\n\n" +
+			"var yamlUser, inputUser = yaml.Marshal, input.DefaultUI\n",
+		generate.Refactor{Find: "(yaml|input)", Replace: "${1}2", IsRegex: true},
+		"package user\n\nimport (\n\t\"gopkg.in/yaml.v3\"\n\n\tinput \"github.com/tcnksm/go-input\"\n)\n\n" +
+			"var yaml2User, input2User = yaml.Marshal, input.DefaultUI\n",
+		2,
+		false,
+	},
+	{
+		"Invalid identifier",
+		"package user\n\ntype User struct{}\n",
+		generate.Refactor{Find: "User", Replace: "Web Server"},
+		"",
+		0,
+		true,
+	},
+	{
+		"Invalid go source",
+		"not go",
+		generate.Refactor{Find: "User", Replace: "Order"},
+		"",
+		0,
+		true,
+	},
+}
+
+//TestIdentifierRefactor is the test suite for the identifier refactor
+func TestIdentifierRefactor(t *testing.T) {
+	for _, v := range identifiertcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			file := filepath.Join(t.TempDir(), "user.go")
+			err := ioutil.WriteFile(file, []byte(v.Source), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := v.Refactor
+			r.Name, r.Source = "identifier", generate.NewIdentifierRefactor()
+			err = r.Do(file)
+			if err == nil && v.Error {
+				t.Error("Expected an error. Got none.")
+				return
+			}
+			if err != nil && !v.Error {
+				t.Error("Didn't expect an error. Got one", err.Error())
+				return
+			}
+			if v.Error {
+				return
+			}
+			if r.Matches != v.Matches {
+				t.Error("Expected", v.Matches, "matches. Got", r.Matches)
+			}
+			b, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != v.Expected {
+				t.Errorf("Expected\n%s\nGot\n%s", v.Expected, string(b))
+			}
+		})
+	}
+}
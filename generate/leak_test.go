@@ -0,0 +1,81 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package generate_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/generate"
+	"go.uber.org/goleak"
+)
+
+/*
+ * This file contains the tests checking that the refactor sources stop on errors and cancellation without leaking goroutines
+ */
+
+//TestMain fails the tests of the package if any goroutine is left running after them
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+//leakSource is the go source refactored by the leak tests
+const leakSource = "// Copyright 2019 Cuttle.ai\n\n//Package log prints logs\npackage log\n\nimport \"github.com/cuttle-ai/web-starter/boilerplate/config\"\n\n//Name is the name\nconst Name = \"web-server\"\n\nvar c config.Config\n"
+
+var leaktcs = []struct {
+	Name   string
+	Source generate.RefactorSource
+}{
+	{"Package refactor", generate.NewPackageRefactor()},
+	{"Comment refactor", generate.NewCommentRefactor()},
+	{"Non go file refactor", generate.NewNonGoFileRefactor()},
+	{"String literal refactor", generate.NewStringLiteralRefactor()},
+	{"Identifier refactor", generate.NewIdentifierRefactor()},
+	{"Template refactor", generate.NewTemplateRefactor(nil)},
+	{"Header refactor", generate.NewHeaderRefactor("Copyright 2020 Acme")},
+}
+
+//TestApplyCancelled checks that the refactor sources stop when the context is cancelled
+func TestApplyCancelled(t *testing.T) {
+	for _, v := range leaktcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			defer goleak.VerifyNone(t)
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			f := generate.NewFile("log.go", []byte(leakSource))
+			r := generate.Refactor{Name: v.Name, Find: "web-server", Replace: "orders", Source: v.Source}
+			err := r.DoFile(ctx, f)
+			if !errors.Is(err, context.Canceled) {
+				t.Error("Expected the context to be cancelled. Got", err)
+			}
+		})
+	}
+}
+
+//TestApplyReplaceError checks that the refactor sources stop at the first error of the replace func
+func TestApplyReplaceError(t *testing.T) {
+	errReplace := errors.New("replace failed")
+	for _, v := range leaktcs[:5] {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			defer goleak.VerifyNone(t)
+			calls := 0
+			f := generate.NewFile("log.go", []byte(leakSource))
+			err := v.Source.Apply(context.Background(), f, func(s string) (string, error) {
+				calls++
+				return s, errReplace
+			})
+			if !errors.Is(err, errReplace) {
+				t.Error("Expected the error of the replace func. Got", err)
+			}
+			if calls != 1 {
+				t.Error("Expected the refactoring to stop after the first error. Replace was called", calls, "times")
+			}
+		})
+	}
+}
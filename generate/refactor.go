@@ -5,6 +5,7 @@
 package generate
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"regexp"
@@ -23,32 +24,72 @@ type Refactor struct {
 	Find string
 	//Replace is the string to be replaced in the place of the string to be found
 	Replace string
-	//IsRegex indicates that the string to be found is a regular expression
+	//IsRegex indicates that the string to be found is a regular expression.
+	//The replace string can then refer to the capture groups of the expression like $1 or ${name}
 	IsRegex bool
+	//ExpectMatches fails the refactor if the string to be found didn't match anything in the file.
+	//It catches the placeholders that no longer exist in the source
+	ExpectMatches bool
 	//Source is the source to be refactored
 	Source RefactorSource
+	//Matches is the number of matches of the string to be found in the last file refactored
+	Matches int
+	//regex is the compiled regular expression of the string to be found
+	regex *regexp.Regexp
+}
+
+//ErrNoMatches is the error returned when a refactor expecting matches didn't match anything
+var ErrNoMatches = errors.New("the refactor didn't match anything")
+
+//Compile compiles the string to be found if the refactor is a regex based one.
+//It returns an error if the string to be found is not a valid regular expression.
+//Do compiles the refactor if it isn't compiled already.
+func (r *Refactor) Compile() error {
+	if !r.IsRegex || r.regex != nil {
+		return nil
+	}
+	reg, err := regexp.Compile(r.Find)
+	if err != nil {
+		return fmt.Errorf("invalid regular expression %q in the refactor %s: %v", r.Find, r.Name, err)
+	}
+	r.regex = reg
+	return nil
 }
 
 //RefactorSource has to be implmented by any source to act as an source code to be refactored
 type RefactorSource interface {
-	//Initiate will read the given file and initiate the refactoring. Through the returned output
-	//channel the code to be refactored will be streamed and through the in channel it will replace the code with the refactored one.
-	//During processing of each source code stream, if any error occurs it will be pushed to the error channel. Else nil will be pushed to it.
-	//The absolute path to the file from the refactor source code string has to be recovered
-	//This method also returns the error if any else nil is returned
-	Initiate(file string, in chan string) (chan string, chan error, error)
+	//Apply will refactor the given in memory file. It calls the replace func with each piece of code to be refactored
+	//in the file, like the imports or the comments, and puts the returned code in its place. The file is written by the caller.
+	//If the replace func returns an error or the context is cancelled, it stops refactoring and returns the error.
+	Apply(ctx context.Context, f *File, replace func(string) (string, error)) error
 }
 
-//Do will make the necessary changes in the given file as per the reafctor
+//Do will make the necessary changes in the given file as per the reafctor and overwrite it
 //Will return an error something unexpected comes up or refactoring fails
-//This method requires the absolute path to the file.
-func (r Refactor) Do(file string) error {
+//The number of matches in the file is recorded in Matches.
+//This method requires the absolute path to the file. To make many refactors in a file, use DoFile
+//so that the file is read and written only once.
+func (r *Refactor) Do(file string) error {
+	f, err := ReadFile(file)
+	if err != nil {
+		return err
+	}
+	err = r.DoFile(context.Background(), f)
+	if err != nil {
+		return err
+	}
+	return f.Write(file)
+}
+
+//DoFile will make the necessary changes in the given in memory file as per the refactor
+//Will return an error something unexpected comes up, refactoring fails or the context is cancelled
+//The number of matches in the file is recorded in Matches.
+func (r *Refactor) DoFile(ctx context.Context, f *File) error {
 	/*
 	 * Will check whether the refactor source is nil or not
-	 * Will initiate the refactor source
-	 * Will get the source code to be refactored
-	 * If the refactor is a regex based one, will handle in accordance
-	 * Else simple string find and replace will be done
+	 * Will compile the refactor
+	 * Will apply the refactor source with the find and replace of the refactor
+	 * Finally will check the matches if they are expected
 	 */
 
 	//checking for the nil source
@@ -57,42 +98,42 @@ func (r Refactor) Do(file string) error {
 		return errors.New("the refactor source is nil. Not refactoring " + r.Name)
 	}
 
-	//initiating the source
-	in := make(chan string)
-	out, errChan, err := r.Source.Initiate(file, in)
+	//compiling the refactor
+	err := r.Compile()
+	if err != nil {
+		return err
+	}
+
+	//applying the source
+	r.Matches = 0
+	err = r.Source.Apply(ctx, f, r.replace)
 	if err != nil {
-		//error while initiating the source for the file
-		fmt.Println("Error while initiating the source for the file", file)
+		//error while refactoring the file
+		fmt.Println("Error while refactoring", r.Name, "in the file", f.Name)
 		return err
 	}
 
-	for source := range in {
-		//handling the regex type of refactoring
-		if r.IsRegex {
-			reg := regexp.MustCompile(r.Find)
-			//if the count is < 1 then the count is infinite
-			//if the count is > 1 find the occurrence till count < 1
-			out <- reg.ReplaceAllString(source, r.Replace)
-			err := <-errChan
-			if err != nil {
-				//error while setting refactored string
-				fmt.Println("Error while replacing the occurrence refactoring", r.Name, file, r.Find, "->", r.Replace)
-				return err
-			}
-			continue
-		}
-
-		//handling simple string replacement
-		out <- strings.Replace(source, r.Find, r.Replace, -1)
-		err := <-errChan
-		if err != nil {
-			//error while setting refactored string
-			fmt.Println("Error while replacing the occurrence refactoring", r.Name, file, r.Find, "->", r.Replace)
-			return err
-		}
+	//checking the matches
+	if r.ExpectMatches && r.Matches == 0 {
+		return fmt.Errorf("%w: %q in %s", ErrNoMatches, r.Find, f.Name)
+	}
+	return nil
+}
+
+//replace replaces the string to be found in the given code and counts the matches.
+//If the refactor is a regex based one, will handle in accordance. Else simple string find and replace will be done
+func (r *Refactor) replace(code string) (string, error) {
+	//handling the regex type of refactoring
+	if r.IsRegex {
+		r.Matches += len(r.regex.FindAllStringIndex(code, -1))
+		return r.regex.ReplaceAllString(code, r.Replace), nil
+	}
+
+	//handling simple string replacement
+	if len(r.Find) > 0 {
+		r.Matches += strings.Count(code, r.Find)
 	}
-	err = <-errChan
-	return err
+	return strings.Replace(code, r.Find, r.Replace, -1), nil
 }
 
 //String is the stringer implementation of the Refactor
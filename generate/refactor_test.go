@@ -5,6 +5,7 @@
 package generate_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -22,39 +23,35 @@ import (
 
 type errorRefactor struct{}
 
-func (e errorRefactor) Initiate(file string, out chan string) (chan string, chan error, error) {
-	in := make(chan string)
-	erCh := make(chan error)
-	go func(in, out chan string, erCh chan error) {
-		out <- ""
-		<-in
-		erCh <- errors.New("Mocking an error")
-		erCh <- nil
-	}(in, out, erCh)
-	return in, erCh, nil
+func (e errorRefactor) Apply(ctx context.Context, file *generate.File, replace func(string) (string, error)) error {
+	_, err := replace("")
+	if err != nil {
+		return err
+	}
+	return errors.New("Mocking an error")
 }
 
 func commonValidTest(file string, checkOld, checkNew bool, oldStr, newStr string, source generate.RefactorSource) (string, bool) {
-	out := make(chan string)
-	in, erCh, err := source.Initiate(testdataDir+string([]rune{filepath.Separator})+file, out)
+	f, err := generate.ReadFile(testdataDir + string([]rune{filepath.Separator}) + file)
 	if err != nil {
-		//error while opening the main_copy.go file to validate
-		fmt.Println("Error while reading the source of testdata/main_copy.go")
 		return err.Error(), false
 	}
 	foundNew := false
 	foundOld := false
-	for s := range out {
+	err = source.Apply(context.Background(), f, func(s string) (string, error) {
 		if strings.Contains(s, oldStr) {
 			foundOld = true
 		}
 		if strings.Contains(s, newStr) {
 			foundNew = true
 		}
-		in <- s
-		<-erCh
+		return s, nil
+	})
+	if err != nil {
+		//error while opening the main_copy.go file to validate
+		fmt.Println("Error while reading the source of testdata/main_copy.go")
+		return err.Error(), false
 	}
-	<-erCh
 	if (checkOld && foundOld) || (!checkOld && !foundOld) {
 		//the string shouldn'thave been there if sucessfull test
 		return "Expected the string cuttle-ai/web-starter to be not found in testdata/main_copy.go", false
@@ -418,3 +415,92 @@ func TestRefactorDo(t *testing.T) {
 		})
 	}
 }
+
+var regextcs = []struct {
+	Name     string
+	Content  string
+	Refactor generate.Refactor
+	Expected string
+	Matches  int
+	Error    error
+}{
+	{
+		"Regex capture groups",
+		"port: 8080\nhost: localhost\n",
+		generate.Refactor{Name: "yaml to env", Find: `^(\w+): (.*)$`, Replace: "${1}=$2", IsRegex: true},
+		"port=8080\nhost=localhost\n",
+		2,
+		nil,
+	},
+	{
+		"Plain string match count",
+		"web-server web-server\nweb-server\n",
+		generate.Refactor{Name: "name", Find: "web-server", Replace: "orders"},
+		"orders orders\norders\n",
+		3,
+		nil,
+	},
+	{
+		"Invalid regex",
+		"port: 8080\n",
+		generate.Refactor{Name: "invalid", Find: `(\w+`, Replace: "$1", IsRegex: true},
+		"port: 8080\n",
+		0,
+		errors.New("invalid regular expression"),
+	},
+	{
+		"No matches expected",
+		"port: 8080\n",
+		generate.Refactor{Name: "stale", Find: "{{.Port}}", Replace: "9090", ExpectMatches: true},
+		"port: 8080\n",
+		0,
+		generate.ErrNoMatches,
+	},
+	{
+		"Matches expected",
+		"port: {{.Port}}\n",
+		generate.Refactor{Name: "port", Find: `\{\{\.Port\}\}`, Replace: "9090", IsRegex: true, ExpectMatches: true},
+		"port: 9090\n",
+		1,
+		nil,
+	},
+}
+
+//TestRefactorRegex is the test suite for the regex handling, match counts and expected matches of the refactors
+func TestRefactorRegex(t *testing.T) {
+	for _, v := range regextcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			file := filepath.Join(t.TempDir(), "config.yaml")
+			err := os.WriteFile(file, []byte(v.Content), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := v.Refactor
+			r.Source = generate.NewNonGoFileRefactor()
+			err = r.Do(file)
+			if err == nil && v.Error != nil {
+				t.Error("Expected an error.", v.Error.Error(), "Got none.")
+				return
+			}
+			if err != nil && v.Error == nil {
+				t.Error("Didn't expect an error. Got one", err.Error())
+				return
+			}
+			if err != nil && !errors.Is(err, v.Error) && !strings.Contains(err.Error(), v.Error.Error()) {
+				t.Error("Expected the error", v.Error.Error(), "Got", err.Error())
+				return
+			}
+			if r.Matches != v.Matches {
+				t.Error("Expected", v.Matches, "matches. Got", r.Matches)
+			}
+			b, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != v.Expected {
+				t.Errorf("Expected\n%s\nGot\n%s", v.Expected, string(b))
+			}
+		})
+	}
+}
@@ -5,9 +5,12 @@
 package generate
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 )
 
@@ -17,47 +20,163 @@ import (
 
 //Source holds the source file and list of refactors to be done on it
 type Source struct {
-	//Path is absolute path at which the source file exists
+	//FS is the file system from which the source file is read. If it is nil, the source file is read from the os file system
+	FS fs.FS
+	//Path is the path at which the source file exists. If FS is given, it is the slash separated path of the
+	//directory in the FS. Else it is the absolute path in the os file system
 	Path string
 	//FileName is the name of the source file
 	FileName string
 	//RelativeDestination is the relative path in the destination to put the refactored file
 	RelativeDestination string
+	//DestinationName is the name of the refactored file in the destination. Defaults to the name of the source file.
+	//It lets the templates have files like _gitignore which are generated as .gitignore
+	DestinationName string
+	//Mode is the permission of the generated file. If it is zero, the file is generated with 0755 if the
+	//source file is executable and 0644 otherwise
+	Mode fs.FileMode
+	//Raw indicates that the source file has to be copied as it is without doing the refactors.
+	//The binary files are always copied as they are
+	Raw bool
 	//Refactors is the list of refactors
 	Refactors []Refactor
 }
 
 //Name returns the name of the source
 func (s *Source) Name() string {
+	if s.FS != nil {
+		return path.Join(s.Path, s.FileName)
+	}
 	return s.Path + string([]rune{filepath.Separator}) + s.FileName
 }
 
+//fsys returns the file system from which the source file has to be read
+func (s *Source) fsys() fs.FS {
+	if s.FS != nil {
+		return s.FS
+	}
+	return osFS{}
+}
+
+//osFS is the fs.FS implementation of the os file system. Unlike os.DirFS it accepts absolute and
+//os specific paths since the source paths are not relative to any root.
+type osFS struct{}
+
+//Open opens the named file in the os file system
+func (osFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
 //Generate will generate a source file in the given destination path.
-//It will copy the source file and makes the required refactors in the
-//newly created destination file.
+//It will read the source file into memory, make the required refactors in it one after
+//the other and write the refactored file to the destination once. Raw and binary source files are copied as they are.
 //The required destination directory has to be provided as argument. The file name will
-//be same as the source. If the file name also has to be changed, it has to be specified under the
-//refactor list.
+//be same as the source unless the destination name is given.
 func (s *Source) Generate(dst string) error {
+	return s.GenerateContext(context.Background(), dst)
+}
+
+//GenerateContext is Generate which stops refactoring the source file once the given context is cancelled.
+//Nothing is written to the destination if the context is cancelled before the refactors are done.
+func (s *Source) GenerateContext(ctx context.Context, dst string) error {
 	/*
-	 * We will copy the source file to the destination
-	 * We will open the destination file
-	 * Will make all the refactors
+	 * We will read the source file
+	 * Will make all the refactors unless the source is raw or binary
+	 * Then we will write the refactored file to the destination
 	 */
-	//copying the source file
-	d, err := s.Copy(dst)
+	//reading the source file
+	f, err := s.Read()
 	if err != nil {
-		//Error while copying the file to the destination
+		//Error while reading the source file
 		fmt.Println("Error while generating the source file", s.Name())
-		return err
+		return &Error{Source: s.Name(), Err: err}
 	}
 
 	//will iterate through the refactors and do them
-	for _, v := range s.Refactors {
-		err = v.Do(d)
+	for i := range s.Refactors {
+		if s.Raw || f.IsBinary() {
+			break
+		}
+		v := &s.Refactors[i]
+		err = v.DoFile(ctx, f)
+		if err != nil {
+			fmt.Println("Error while making the refactor", v.String(), "in the source file", s.Name())
+			return &Error{Source: s.Name(), Refactor: v.String(), Err: err}
+		}
+	}
+
+	//writing the refactored file to the destination
+	d := s.Destination(dst)
+	err = os.MkdirAll(filepath.Dir(d), 0775)
+	if err == nil {
+		err = f.Write(d)
+	}
+	if err != nil {
+		//error while writing the destination file
+		fmt.Println("Error while writing the destination file", d)
+		return &Error{Source: s.Name(), Err: err}
+	}
+	return nil
+}
+
+//Read reads the source file into memory. It has to be a regular file.
+//The file gets the permission with which it has to be generated.
+func (s *Source) Read() (*File, error) {
+	info, err := s.stat()
+	if err != nil {
+		return nil, err
+	}
+	b, err := fs.ReadFile(s.fsys(), s.Name())
+	if err != nil {
+		//error while reading the source file
+		fmt.Println("Error while reading the source file", s.Name())
+		return nil, err
+	}
+	f := NewFile(s.Name(), b)
+	f.Mode = s.mode(info)
+	return f, nil
+}
+
+//mode returns the permission of the generated file for the source file with the given info
+func (s *Source) mode(info fs.FileInfo) fs.FileMode {
+	if s.Mode != 0 {
+		return s.Mode
+	}
+	if info.Mode()&0111 != 0 {
+		return 0755
+	}
+	return 0644
+}
+
+//stat checks that the source file exists and is a regular file. It returns the info of the source file
+func (s *Source) stat() (fs.FileInfo, error) {
+	/*
+	 * First we need to check whether file exists in the given source
+	 * Checking whether the file is a regular file itself
+	 */
+	//checking whether the source file exists and is a regular file
+	sourceFileStat, err := fs.Stat(s.fsys(), s.Name())
+	if err != nil {
+		//checking whether the source file
+		fmt.Println("Error while reading info of the source file", s.Name())
+		return nil, err
+	}
+
+	//checking whether the source file is regular or not
+	if !sourceFileStat.Mode().IsRegular() {
+		fmt.Println("The given source file is not regular", s.Name())
+		return nil, fmt.Errorf("%s is not a regular file", s.Name())
+	}
+	return sourceFileStat, nil
+}
+
+//Compile compiles the regex based refactors of the source so that an invalid regular expression is
+//reported before generating any file
+func (s *Source) Compile() error {
+	for i := range s.Refactors {
+		err := s.Refactors[i].Compile()
 		if err != nil {
-			fmt.Println("Error while making the refactor", v.String(), "in the destination file for", d)
-			return err
+			return &Error{Source: s.Name(), Refactor: s.Refactors[i].String(), Err: err}
 		}
 	}
 	return nil
@@ -65,66 +184,80 @@ func (s *Source) Generate(dst string) error {
 
 var separator = string([]rune{filepath.Separator})
 
+//RelativePath returns the path of the file that will be created by the source relative to the destination directory
+func (s *Source) RelativePath() string {
+	name := s.FileName
+	if len(s.DestinationName) > 0 {
+		name = s.DestinationName
+	}
+	if len(s.RelativeDestination) > 0 {
+		return s.RelativeDestination + separator + name
+	}
+	return name
+}
+
+//Destination returns the absolute path of the file that will be created by the source
+//in the given destination directory.
+func (s *Source) Destination(dst string) string {
+	return dst + separator + s.RelativePath()
+}
+
 //Copy copies a source file to a given destination. The destination shouldn't have the
 //destination file name. It should only contain the absolute path to the destination directory.
 //If any error occurs while copying, like unsuccessful copying of the file, or unsuccessful creation of the
 //destination directory, it will be reported back. It will also return the absolute path to the destination file.
+//The copied file gets the permission with which the source has to be generated.
 func (s *Source) Copy(dst string) (string, error) {
 	/*
-	 * First we need to check whether file exists in the given source
-	 * Checking whether the file is a regular file itself
+	 * First we need to check whether file exists in the given source and is a regular file
 	 * Create the directories in destination if not existing
 	 * Identify the destination file name
 	 * Copy the file to the destination
 	 */
 	//checking whether the source file exists and is a regular file
-	sourceFileStat, err := os.Stat(s.Name())
+	info, err := s.stat()
 	if err != nil {
-		//checking whether the source file
-		fmt.Println("Error while reading info of the source file", s.Name())
 		return "", err
 	}
 
-	//checking whether the source file is regular or not
-	if !sourceFileStat.Mode().IsRegular() {
-		fmt.Println("The given source file is not regular", s.Name())
-		return "", fmt.Errorf("%s is not a regular file", s.Name())
-	}
+	//identifying the destination filename
+	dstF := s.Destination(dst)
 
 	//creating the destination directory if not existing
-	newD := dst
-	if len(s.RelativeDestination) > 0 {
-		newD = dst + separator + s.RelativeDestination
-	}
-	err = os.MkdirAll(newD, 0775)
+	err = os.MkdirAll(filepath.Dir(dstF), 0775)
 	if err != nil {
 		//error while creating the destination directories
 		fmt.Println("Error while creating the destination directories", dst)
 		return "", err
 	}
 
-	//identifying the destination filename
-	dstF := newD + separator + s.FileName
-
 	//copying the source file to the destination
-	source, _ := os.Open(s.Name())
-	//since the stats of the file is already checked, there is less chance that the
-	//source cannot be opened. Hence we are ignoring the error in opening the source file
+	source, err := s.fsys().Open(s.Name())
+	if err != nil {
+		//error while opening the source file
+		fmt.Println("Error while opening the source file", s.Name())
+		return "", err
+	}
 	defer source.Close()
-	dF, _ := os.Create(dstF)
-	//There is less chance that the destination file cannot be created.
-	//Hence we are ignoring the error. If permission issues creep in
-	//the application is meant to fail by default. So it should be fine
-	defer dF.Close()
-	//copying
+	dF, err := os.OpenFile(dstF, os.O_RDWR|os.O_CREATE|os.O_TRUNC, s.mode(info))
+	if err != nil {
+		//error while creating the destination file
+		fmt.Println("Error while creating the destination file", dstF)
+		return "", err
+	}
 	_, err = io.Copy(dF, source)
+	if err == nil {
+		//the permission is given only to the new files while opening. So setting it for the existing ones
+		err = dF.Chmod(s.mode(info))
+	}
+	if cErr := dF.Close(); err == nil {
+		err = cErr
+	}
 	if err != nil {
 		//error while copying the destination file
-		fmt.Println("Error while creating the destination file", dF)
+		fmt.Println("Error while copying to the destination file", dstF)
 		return "", err
 	}
-	//since the source and destination are meant to exist, there is less chance that the
-	//copying file. Permission issues can creep in. In that case the application is meant to fail.
 
 	return dstF, nil
 }
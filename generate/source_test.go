@@ -7,10 +7,12 @@ package generate_test
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"syscall"
 	"testing"
+	"testing/fstest"
 
 	"github.com/cuttle-ai/web-starter/generate"
 )
@@ -58,6 +60,50 @@ var sourcetcs = []struct {
 			return "", true
 		},
 	},
+	{
+		"Copy file from a file system",
+		generate.Source{
+			FS: fstest.MapFS{
+				"boilerplate/main.go": &fstest.MapFile{Data: []byte("package main\n")},
+			},
+			Path:      "boilerplate",
+			FileName:  "main.go",
+			Refactors: []generate.Refactor{},
+		},
+		testdataDir + string([]rune{filepath.Separator}) + "copied",
+		nil,
+		func() {},
+		func() {
+			os.RemoveAll(testdataDir + string([]rune{filepath.Separator}) + "copied")
+		},
+		func() (string, bool) {
+			b, err := ioutil.ReadFile(testdataDir + string([]rune{filepath.Separator}) +
+				"copied" + string([]rune{filepath.Separator}) + "main.go")
+			if err != nil {
+				return err.Error(), false
+			}
+			if string(b) != "package main\n" {
+				return "Expected the copied file to have the content of the file in the file system. Got " + string(b), false
+			}
+			return "", true
+		},
+	},
+	{
+		"File doesn't exist in the file system",
+		generate.Source{
+			FS:        os.DirFS(testdataDir),
+			Path:      ".",
+			FileName:  "main1.go",
+			Refactors: []generate.Refactor{},
+		},
+		testdataDir + string([]rune{filepath.Separator}) + "copied",
+		errors.New("Path doesn't exist"),
+		func() {},
+		func() {},
+		func() (string, bool) {
+			return "", true
+		},
+	},
 	{
 		"File doesn't exist",
 		generate.Source{
@@ -94,10 +140,11 @@ var sourcetcs = []struct {
 			os.RemoveAll(testdataDir + string([]rune{filepath.Separator}) + "copied")
 		},
 		func() (string, bool) {
+			//the file is written only after all the refactors are done
 			_, err := os.Stat(testdataDir + string([]rune{filepath.Separator}) +
 				"copied" + string([]rune{filepath.Separator}) + "main.go")
-			if err != nil {
-				return err.Error(), false
+			if !os.IsNotExist(err) {
+				return "Expected the file not to be written when a refactor fails", false
 			}
 			return "", true
 		},
@@ -170,3 +217,107 @@ func TestSourceGenerate(t *testing.T) {
 		})
 	}
 }
+
+//TestSourceCopyErrors checks that the errors while copying the source file are returned
+func TestSourceCopyErrors(t *testing.T) {
+	fmt.Println("Testing the copy errors")
+	dst := t.TempDir()
+	//the destination file can't be created since a directory exists in its place
+	err := os.Mkdir(filepath.Join(dst, "main.go"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := generate.Source{Path: testdataDir, FileName: "main.go"}
+	_, err = s.Copy(dst)
+	if !errors.Is(err, syscall.EISDIR) {
+		t.Error("Expected the error while creating the destination file. Got", err)
+	}
+	err = s.Generate(dst)
+	if !errors.Is(err, syscall.EISDIR) {
+		t.Error("Expected the error while writing the destination file. Got", err)
+	}
+}
+
+var assettcs = []struct {
+	Name    string
+	Source  generate.Source
+	Path    string
+	Content string
+	Mode    os.FileMode
+}{
+	{
+		"Executable bit is preserved",
+		generate.Source{FileName: "run.sh"},
+		"run.sh",
+		"#!/bin/sh\necho orders\n",
+		0755,
+	},
+	{
+		"Destination is renamed",
+		generate.Source{FileName: "_gitignore", DestinationName: ".gitignore"},
+		".gitignore",
+		"*.exe\n",
+		0644,
+	},
+	{
+		"Mode is given",
+		generate.Source{FileName: "_gitignore", Mode: 0600},
+		"_gitignore",
+		"*.exe\n",
+		0600,
+	},
+	{
+		"Binary files are not refactored",
+		generate.Source{FileName: "favicon.ico"},
+		"favicon.ico",
+		"\x00\x01web-server{{",
+		0644,
+	},
+	{
+		"Raw files are not refactored",
+		generate.Source{FileName: "page.tmpl", Raw: true},
+		"page.tmpl",
+		"<h1>{{.Title}} web-server</h1>\n",
+		0644,
+	},
+}
+
+//TestSourceAssets checks the permissions, renames and the binary and raw files of the sources
+func TestSourceAssets(t *testing.T) {
+	templates := fstest.MapFS{
+		"assets/run.sh":      &fstest.MapFile{Data: []byte("#!/bin/sh\necho web-server\n"), Mode: 0755},
+		"assets/_gitignore":  &fstest.MapFile{Data: []byte("*.exe\n"), Mode: 0644},
+		"assets/favicon.ico": &fstest.MapFile{Data: []byte("\x00\x01web-server{{"), Mode: 0644},
+		"assets/page.tmpl":   &fstest.MapFile{Data: []byte("<h1>{{.Title}} web-server</h1>\n"), Mode: 0644},
+	}
+	for _, v := range assettcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			dst := t.TempDir()
+			s := v.Source
+			s.FS, s.Path = templates, "assets"
+			s.Refactors = []generate.Refactor{
+				{Name: "template", Source: generate.NewTemplateRefactor(nil)},
+				{Name: "name", Find: "web-server", Replace: "orders", Source: generate.NewNonGoFileRefactor()},
+			}
+			err := s.Generate(dst)
+			if err != nil {
+				t.Fatal("Didn't expect an error. Got one", err)
+			}
+			b, err := ioutil.ReadFile(filepath.Join(dst, v.Path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != v.Content {
+				t.Errorf("Expected\n%q\nGot\n%q", v.Content, string(b))
+			}
+			info, err := os.Stat(filepath.Join(dst, v.Path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != v.Mode {
+				t.Error("Expected the permission", v.Mode, "Got", info.Mode().Perm())
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package generate
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+ * This file has the defintions of the string literal refactor struct which implements the RefactorSource.
+ */
+
+//StringLiteralRefactor is to refactor the string literals present in a go source code file like the constants and route patterns.
+//The import paths are left to the package refactor.
+type StringLiteralRefactor struct{}
+
+//NewStringLiteralRefactor is the constructor for the string literal refactor
+func NewStringLiteralRefactor() StringLiteralRefactor {
+	return StringLiteralRefactor{}
+}
+
+//Apply will refactor the string literals of the go source file provided
+//It will call the replace func with the unquoted value of each string literal. Only the literals whose value changed
+//are quoted again, so the rest of them are left as they are.
+//If the replace func returns an error or the context is cancelled, the refactoring is stopped and the error is returned
+func (s StringLiteralRefactor) Apply(ctx context.Context, file *File, replace func(string) (string, error)) error {
+	/*
+	 * We will get the syntax tree of the source file
+	 * We will find the string literals except the import paths
+	 * Will replace the value of each of them
+	 * Will quote the value again if it changed
+	 * The refactored nodes are part of the syntax tree of the file
+	 */
+	//getting the syntax tree of the source file
+	_, f, err := file.AST()
+	if err != nil {
+		return err
+	}
+
+	//finding the string literals
+	lits := []*ast.BasicLit{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch v := n.(type) {
+		case *ast.ImportSpec:
+			return false
+		case *ast.BasicLit:
+			if v.Kind == token.STRING {
+				lits = append(lits, v)
+			}
+		}
+		return true
+	})
+
+	//replacing the values of the literals
+	for _, v := range lits {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		value, err := strconv.Unquote(v.Value)
+		if err != nil {
+			//error while unquoting the literal
+			fmt.Println("Error while unquoting the string literal", v.Value, "in", file.Name)
+			return err
+		}
+		nV, err := replace(value)
+		if err != nil {
+			return err
+		}
+		if nV != value {
+			v.Value = quote(nV, strings.HasPrefix(v.Value, "`"))
+		}
+	}
+	return nil
+}
+
+//quote quotes the given value as a go string literal. Raw string literals are kept raw if the value can be one.
+func quote(value string, raw bool) string {
+	if raw && utf8.ValidString(value) && !strings.ContainsAny(value, "`\r") {
+		return "`" + value + "`"
+	}
+	return strconv.Quote(value)
+}
@@ -0,0 +1,106 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package generate_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/generate"
+)
+
+/*
+ * This file contains the tests for the source code of stringliteralrefactor.go
+ */
+
+var stringliteraltcs = []struct {
+	Name     string
+	Source   string
+	Refactor generate.Refactor
+	Expected string
+	Matches  int
+	Error    bool
+}{
+	{
+		"Constant is renamed and quoted",
+		"package version\n\nimport \"github.com/web-server/log\"\n\nconst AppName = \"web-server\"\n\n// web-server is left in the comments\nvar l = log.New(\"web-server\")\n",
+		generate.Refactor{Find: "web-server", Replace: `Jane's "orders"`},
+		"package version\n\nimport \"github.com/web-server/log\"\n\nconst AppName = \"Jane's \\\"orders\\\"\"\n\n// web-server is left in the comments\nvar l = log.New(\"Jane's \\\"orders\\\"\")\n",
+		2,
+		false,
+	},
+	{
+		"Raw strings are kept raw",
+		"package routes\n\nconst pattern = `/web-server/{id}`\n",
+		generate.Refactor{Find: "web-server", Replace: "orders"},
+		"package routes\n\nconst pattern = `/orders/{id}`\n",
+		1,
+		false,
+	},
+	{
+		"Route patterns with capture groups",
+		"package routes\n\nvar routes = []string{\"/v1/users\", \"/v1/users/{id}\", \"GET\"}\n",
+		generate.Refactor{Find: `^/v1/users(.*)$`, Replace: "/v1/orders$1", IsRegex: true},
+		"package routes\n\nvar routes = []string{\"/v1/orders\", \"/v1/orders/{id}\", \"GET\"}\n",
+		2,
+		false,
+	},
+	{
+		"Unchanged literals keep their escapes",
+		"package log\n\nconst a, b = \"\\x41pp\", \"web-server\"\n",
+		generate.Refactor{Find: "web-server", Replace: "orders"},
+		"package log\n\nconst a, b = \"\\x41pp\", \"orders\"\n",
+		1,
+		false,
+	},
+	{
+		"Invalid go source",
+		"not go",
+		generate.Refactor{Find: "web-server", Replace: "orders"},
+		"",
+		0,
+		true,
+	},
+}
+
+//TestStringLiteralRefactor is the test suite for the string literal refactor
+func TestStringLiteralRefactor(t *testing.T) {
+	for _, v := range stringliteraltcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			file := filepath.Join(t.TempDir(), "version.go")
+			err := ioutil.WriteFile(file, []byte(v.Source), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := v.Refactor
+			r.Name, r.Source = "string literal", generate.NewStringLiteralRefactor()
+			err = r.Do(file)
+			if err == nil && v.Error {
+				t.Error("Expected an error. Got none.")
+				return
+			}
+			if err != nil && !v.Error {
+				t.Error("Didn't expect an error. Got one", err.Error())
+				return
+			}
+			if v.Error {
+				return
+			}
+			if r.Matches != v.Matches {
+				t.Error("Expected", v.Matches, "matches. Got", r.Matches)
+			}
+			b, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != v.Expected {
+				t.Errorf("Expected\n%s\nGot\n%s", v.Expected, string(b))
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package generate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+/*
+ * This file has the defintions of the template refactor struct which implements the RefactorSource.
+ */
+
+//TemplateFuncs are the helper funcs available in the templates rendered by the TemplateRefactor
+var TemplateFuncs = template.FuncMap{
+	//snake converts the given string to snake_case
+	"snake": SnakeCase,
+	//kebab converts the given string to kebab-case
+	"kebab": KebabCase,
+	//camel converts the given string to camelCase
+	"camel": CamelCase,
+	//year returns the current year
+	"year": func() string {
+		return strconv.Itoa(time.Now().Year())
+	},
+	//pkgJoin joins the given elements into a package path
+	"pkgJoin": func(elem ...string) string {
+		return path.Join(elem...)
+	},
+}
+
+//Words splits the given string into lower case words. Words are separated by any non alphanumeric
+//character or by a change from lower case to upper case.
+func Words(s string) []string {
+	ws := []string{}
+	w := []rune{}
+	prev := rune(0)
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(w) > 0 {
+				ws = append(ws, string(w))
+			}
+			w, prev = []rune{}, r
+			continue
+		}
+		if unicode.IsUpper(r) && unicode.IsLower(prev) && len(w) > 0 {
+			ws = append(ws, string(w))
+			w = []rune{}
+		}
+		w = append(w, unicode.ToLower(r))
+		prev = r
+	}
+	if len(w) > 0 {
+		ws = append(ws, string(w))
+	}
+	return ws
+}
+
+//SnakeCase converts the given string to snake_case like web_server
+func SnakeCase(s string) string {
+	return strings.Join(Words(s), "_")
+}
+
+//KebabCase converts the given string to kebab-case like web-server
+func KebabCase(s string) string {
+	return strings.Join(Words(s), "-")
+}
+
+//PascalCase converts the given string to PascalCase like WebServer
+func PascalCase(s string) string {
+	ws := Words(s)
+	for i := range ws {
+		ws[i] = upperFirst(ws[i])
+	}
+	return strings.Join(ws, "")
+}
+
+//CamelCase converts the given string to camelCase like webServer
+func CamelCase(s string) string {
+	ws := Words(s)
+	for i := 1; i < len(ws); i++ {
+		ws[i] = upperFirst(ws[i])
+	}
+	return strings.Join(ws, "")
+}
+
+//upperFirst converts the first letter of the given word to upper case. The word can have non ascii letters.
+func upperFirst(w string) string {
+	r := []rune(w)
+	if len(r) == 0 {
+		return w
+	}
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+//directiveLine matches the lines having only an if, else, end, range or with action in a line comment like
+//	//{{if .Components.Database}}
+//Such lines keep the go source of the template valid and are rendered as the action alone
+var directiveLine = regexp.MustCompile(`(?m)^[ \t]*//[ \t]*(\{\{-?[ \t]*(?:if|else|end|range|with)\b[^}\n]*\}\})[ \t]*\r?\n`)
+
+//TemplateRefactor renders the whole file as a text/template against the given data.
+//The helper funcs in TemplateFuncs are available in the template.
+//A line having only an if, else, end, range or with action in a line comment is removed along with the comment,
+//so that the go source of a template can render a part of it conditionally while being valid go code.
+type TemplateRefactor struct {
+	//Data is the value against which the file is rendered
+	Data interface{}
+}
+
+//NewTemplateRefactor is the constructor for the template refactor
+func NewTemplateRefactor(data interface{}) TemplateRefactor {
+	return TemplateRefactor{Data: data}
+}
+
+//Apply will render the file as a template and set the rendered content in it.
+//Since the whole file is rendered at once, the replace func is not called.
+//So the find and replace of the refactor are not used.
+func (t TemplateRefactor) Apply(ctx context.Context, file *File, replace func(string) (string, error)) error {
+	/*
+	 * We will remove the comments of the directive lines
+	 * We will parse the template in the file
+	 * Then we will render it and set it in the file
+	 */
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	//removing the comments of the directive lines
+	b, err := file.Bytes()
+	if err != nil {
+		return err
+	}
+	b = directiveLine.ReplaceAll(b, []byte("$1"))
+
+	//parsing the template
+	tmpl, err := template.New(filepath.Base(file.Name)).Option("missingkey=error").Funcs(TemplateFuncs).Parse(string(b))
+	if err != nil {
+		//error while parsing the template
+		fmt.Println("Error while parsing the template file", file.Name)
+		return err
+	}
+
+	//rendering the template
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, t.Data)
+	if err != nil {
+		//error while rendering the template
+		fmt.Println("Error while rendering the template file", file.Name)
+		return err
+	}
+	file.SetBytes(buf.Bytes())
+	return nil
+}
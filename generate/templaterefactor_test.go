@@ -0,0 +1,137 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package generate_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cuttle-ai/web-starter/generate"
+)
+
+/*
+ * This file contains the tests for the source code of templaterefactor.go
+ */
+
+type templateData struct {
+	Name        string
+	Description string
+	Package     string
+	Database    bool
+	Author      struct {
+		Name  string
+		Email string
+	}
+}
+
+var templatetcs = []struct {
+	Name     string
+	Template string
+	Data     interface{}
+	Expected string
+	Error    bool
+}{
+	{"Render the fields", "# {{.Name}}\n{{.Description}}", templateData{Name: "Web Server", Description: "Backend"}, "# Web Server\nBackend", false},
+	{"Snake case", "{{snake .Name}}", templateData{Name: "Web Server"}, "web_server", false},
+	{"Kebab case", "{{kebab .Name}}", templateData{Name: "webServer v2"}, "web-server-v2", false},
+	{"Camel case", "{{camel .Name}}", templateData{Name: "web-server_name"}, "webServerName", false},
+	{"Camel case of non ascii words", "{{camel .Name}}", templateData{Name: "größe éclair"}, "größeÉclair", false},
+	{"Current year", "{{year}}", templateData{}, strconv.Itoa(time.Now().Year()), false},
+	{"Package path join", `{{pkgJoin .Package "routes" "response"}}`, templateData{Package: "github.com/jane/orders"}, "github.com/jane/orders/routes/response", false},
+	{"Directive lines rendered", "type A struct {\n\t//{{if .Database}}\n\tDb int\n\t//{{else}}\n\tNoDb bool\n\t//{{end}}\n\tLog int\n}\n", templateData{Database: true}, "type A struct {\n\tDb int\n\tLog int\n}\n", false},
+	{"Directive lines not rendered", "type A struct {\n\t//{{if .Database}}\n\tDb int\n\t//{{end}}\n\tLog int\n}\n", templateData{}, "type A struct {\n\tLog int\n}\n", false},
+	{"Comment having a value is not a directive", "//{{.Name}} {{.Description}}\npackage main\n", templateData{Name: "Orders", Description: "Backend"}, "//Orders Backend\npackage main\n", false},
+	{"Missing field", "{{.Version}}", templateData{}, "", true},
+	{"Invalid template", "{{.Name", templateData{}, "", true},
+}
+
+//TestTemplateRefactor is the test suite for the template refactor
+func TestTemplateRefactor(t *testing.T) {
+	for _, v := range templatetcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			file := filepath.Join(t.TempDir(), "README.md")
+			err := ioutil.WriteFile(file, []byte(v.Template), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = (&generate.Refactor{Name: "template", Source: generate.NewTemplateRefactor(v.Data)}).Do(file)
+			if err == nil && v.Error {
+				t.Error("Expected an error. Got none.")
+				return
+			}
+			if err != nil && !v.Error {
+				t.Error("Didn't expect an error. Got one", err.Error())
+				return
+			}
+			if v.Error {
+				return
+			}
+			b, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != v.Expected {
+				t.Error("Expected", v.Expected, "Got", string(b))
+			}
+		})
+	}
+}
+
+var casetcs = []struct {
+	Name     string
+	Value    string
+	Expected [4]string
+}{
+	{"Words with spaces", "Web Server", [4]string{"web_server", "web-server", "webServer", "WebServer"}},
+	{"Camel case words", "listOrderItems", [4]string{"list_order_items", "list-order-items", "listOrderItems", "ListOrderItems"}},
+	{"Non ascii words", "über straße", [4]string{"über_straße", "über-straße", "überStraße", "ÜberStraße"}},
+	{"Non ascii camel case words", "éclairÉté", [4]string{"éclair_été", "éclair-été", "éclairÉté", "ÉclairÉté"}},
+	{"No words", "-_ ", [4]string{"", "", "", ""}},
+}
+
+//TestCases is the test suite for converting the strings to snake, kebab, camel and pascal case
+func TestCases(t *testing.T) {
+	for _, v := range casetcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			got := [4]string{generate.SnakeCase(v.Value), generate.KebabCase(v.Value), generate.CamelCase(v.Value), generate.PascalCase(v.Value)}
+			if got != v.Expected {
+				t.Error("Expected", v.Expected, "Got", got)
+			}
+		})
+	}
+}
+
+//TestTemplateRefactorTestdata renders the README in the testdata as a template
+func TestTemplateRefactorTestdata(t *testing.T) {
+	b, err := ioutil.ReadFile(testdataDir + string([]rune{filepath.Separator}) + "README.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "README.md")
+	err = ioutil.WriteFile(file, b, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := templateData{Name: "New Name", Description: "New Description", Package: "github.com/melvinodsa/test"}
+	data.Author.Name, data.Author.Email = "Melvin", "melvin@example.com"
+	err = (&generate.Refactor{Name: "template", Source: generate.NewTemplateRefactor(data)}).Do(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(r), "{{") || !strings.Contains(string(r), "Melvin<melvin@example.com>") {
+		t.Error("Expected all the placeholders in the README to be rendered. Got", string(r))
+	}
+}
@@ -1,7 +1,11 @@
 package main
 
-import "github.com/cuttle-ai/web-starter/cmd"
+import (
+	"github.com/cuttle-ai/web-starter/cmd"
+	"github.com/cuttle-ai/web-starter/project"
+)
 
 func main() {
+	project.Templates = templates
 	cmd.Execute()
 }
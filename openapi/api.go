@@ -0,0 +1,843 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"fmt"
+	"go/token"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cuttle-ai/web-starter/generate"
+)
+
+/*
+ * This file contains the code to be generated for an OpenAPI document.
+ * The types of the requests and responses are generated in the api package of the project. The routes of the paths,
+ * the handler funcs decoding the requests of the operations and the stubs of the operations are generated in its routes package.
+ */
+
+//DefaultVersion is the version of the routes if the url of the first server doesn't end with a version like /v2
+const DefaultVersion = "v1"
+
+//Package is the name of the package in the project having the types of the requests and responses
+const Package = "api"
+
+//methodConsts are the names of the constants of the http methods in net/http
+var methodConsts = map[string]string{
+	"GET":     "MethodGet",
+	"HEAD":    "MethodHead",
+	"POST":    "MethodPost",
+	"PUT":     "MethodPut",
+	"PATCH":   "MethodPatch",
+	"DELETE":  "MethodDelete",
+	"OPTIONS": "MethodOptions",
+}
+
+//statusConsts are the names of the constants of the success status codes in net/http
+var statusConsts = map[string]string{
+	"200": "StatusOK",
+	"201": "StatusCreated",
+	"202": "StatusAccepted",
+	"203": "StatusNonAuthoritativeInfo",
+	"204": "StatusNoContent",
+	"205": "StatusResetContent",
+	"206": "StatusPartialContent",
+}
+
+//paramTypes are the go types of the parameters supported
+var paramTypes = map[string]bool{"string": true, "[]string": true, "bool": true, "int32": true, "int64": true, "float32": true, "float64": true}
+
+//versionSegment matches a version in the url of a server like v2
+var versionSegment = regexp.MustCompile(`^v[0-9]+$`)
+
+//pathParam matches a parameter in a path like {id}
+var pathParam = regexp.MustCompile(`{([^{}/]*)}`)
+
+//segmentParam matches a segment of a path which is a parameter as a whole
+var segmentParam = regexp.MustCompile(`^{[^{}]+}$`)
+
+//exportedIdent matches the exported identifiers of the api package in a type like []User
+var exportedIdent = regexp.MustCompile(`(^|[^.\w])([A-Z]\w*)`)
+
+//API is the code generated for an OpenAPI document
+type API struct {
+	//Title of the API
+	Title string
+	//Version is the version of the routes like v1
+	Version string
+	//Types are the types of the requests and responses sorted by their name
+	Types []*Type
+	//Routes are the routes of the paths of the API sorted by their path
+	Routes []*Route
+}
+
+//Type is a type declared in the api package
+type Type struct {
+	//Name of the type
+	Name string
+	//Doc are the lines of the doc comment of the type
+	Doc []string
+	//Fields are the fields of the type if it is a struct
+	Fields []*Field
+	//Underlying is the underlying type if it isn't a struct
+	Underlying string
+	//Alias tells whether the type is an alias of the underlying type
+	Alias bool
+}
+
+//Field is a field of a struct type
+type Field struct {
+	//Name of the field
+	Name string
+	//Type of the field
+	Type string
+	//Tag of the field
+	Tag string
+	//Doc are the lines of the doc comment of the field
+	Doc []string
+	//Embedded tells whether the field is an embedded struct whose fields are promoted
+	Embedded bool
+}
+
+//Route is the route of a path of the API serving all its operations
+type Route struct {
+	//Path is the path as given in the document like /users/{user-id}
+	Path string
+	//Pattern is the pattern of the route with the parameters as go identifiers like /users/{userId}
+	Pattern string
+	//Handler is the handler func of the route serving the operations by their method
+	Handler string
+	//Endpoints are the operations of the path
+	Endpoints []*Endpoint
+	//OtherMethod is the constant in net/http of a method not having an operation in the path. It is empty if all of them have one
+	OtherMethod string
+}
+
+//Endpoint is an operation of the API having a stub in the routes package
+type Endpoint struct {
+	//Name of the stub of the operation
+	Name string
+	//Method is the http method of the operation
+	Method string
+	//MethodConst is the constant of the method in net/http
+	MethodConst string
+	//Path is the path of the operation as given in the document
+	Path string
+	//Doc are the lines of the summary and the description of the operation
+	Doc []string
+	//Params are the parameters of the operation
+	Params []*Param
+	//ParamsType is the type of the parameters of the operation in the routes package. It is empty if there are no parameters
+	ParamsType string
+	//Body is the json body of the requests of the operation. It is nil if there is none
+	Body *Value
+	//Response is the json body of the response of the operation. It is nil if there is none
+	Response *Value
+	//Status is the status code of the successful response in the routes package like http.StatusOK
+	Status string
+}
+
+//Param is a parameter of an operation
+type Param struct {
+	//Name of the parameter as given in the document
+	Name string
+	//Field is the field of the parameter in the type of the parameters
+	Field string
+	//In is the location of the parameter. Any of path, query or header
+	In string
+	//Key is the name of the parameter in the request. The name of the path parameters is their name in the pattern of the route
+	Key string
+	//Required tells whether the parameter is required
+	Required bool
+}
+
+//Values returns the expression of the values of the parameter in the request in the handler funcs of the routes package
+func (p Param) Values() string {
+	switch p.In {
+	case "path":
+		return fmt.Sprintf("[]string{req.PathValue(%q)}", p.Key)
+	case "header":
+		return fmt.Sprintf("req.Header.Values(%q)", p.Key)
+	}
+	return fmt.Sprintf("req.URL.Query()[%q]", p.Key)
+}
+
+//Value is a request or response body in the routes package
+type Value struct {
+	//Type of the value like *api.User or []api.User
+	Type string
+	//Elem is the type the value points to if it is a pointer
+	Elem string
+	//Ptr tells whether the value is a pointer
+	Ptr bool
+}
+
+//ParamTypes returns the types of the parameters of the stub of the operation in the routes package
+func (o Endpoint) ParamTypes() []string {
+	types := []string{"context.Context", "*http.Request"}
+	if len(o.ParamsType) > 0 {
+		types = append(types, o.ParamsType)
+	}
+	if o.Body != nil {
+		types = append(types, o.Body.Type)
+	}
+	return types
+}
+
+//ResultTypes returns the types of the results of the stub of the operation in the routes package
+func (o Endpoint) ResultTypes() []string {
+	if o.Response != nil {
+		return []string{o.Response.Type, "error"}
+	}
+	return []string{"error"}
+}
+
+//Args returns the parameters of the stub of the operation with their names
+func (o Endpoint) Args() string {
+	args := []string{"ctx context.Context", "req *http.Request"}
+	if len(o.ParamsType) > 0 {
+		args = append(args, "params "+o.ParamsType)
+	}
+	if o.Body != nil {
+		args = append(args, "body "+o.Body.Type)
+	}
+	return strings.Join(args, ", ")
+}
+
+//Call returns the arguments with which the stub of the operation is called by its handler func
+func (o Endpoint) Call() string {
+	args := []string{"ctx", "req"}
+	if len(o.ParamsType) > 0 {
+		args = append(args, "params")
+	}
+	if o.Body != nil {
+		args = append(args, "body")
+	}
+	return strings.Join(args, ", ")
+}
+
+//Signature returns the signature of the stub of the operation like func(context.Context, *http.Request) error
+func (o Endpoint) Signature() string {
+	results := strings.Join(o.ResultTypes(), ", ")
+	if o.Response != nil {
+		results = "(" + results + ")"
+	}
+	return "func(" + strings.Join(o.ParamTypes(), ", ") + ") " + results
+}
+
+//Uses tells whether the stub of the operation uses the given package like api or time
+func (o Endpoint) Uses(pkg string) bool {
+	for _, v := range append(o.ParamTypes(), o.ResultTypes()...) {
+		if strings.Contains(v, pkg+".") {
+			return true
+		}
+	}
+	return false
+}
+
+//EndpointsUse tells whether the stubs of the operations use the given package like api or time
+func (a API) EndpointsUse(pkg string) bool {
+	for _, p := range a.Routes {
+		for _, o := range p.Endpoints {
+			if o.Uses(pkg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//UsesJSON tells whether the handler funcs of the routes package decode or encode json
+func (a API) UsesJSON() bool {
+	for _, p := range a.Routes {
+		for _, o := range p.Endpoints {
+			if o.Body != nil || o.Response != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//HasParams tells whether any operation of the API has parameters
+func (a API) HasParams() bool {
+	for _, p := range a.Routes {
+		for _, o := range p.Endpoints {
+			if len(o.Params) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//TypesUse tells whether the types of the api package use the given package like time
+func (a API) TypesUse(pkg string) bool {
+	for _, t := range a.Types {
+		if strings.Contains(t.Underlying, pkg+".") {
+			return true
+		}
+		for _, f := range t.Fields {
+			if strings.Contains(f.Type, pkg+".") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//Identifiers returns the identifiers declared by the code generated in the routes package other than the stubs
+func (a API) Identifiers() []string {
+	ids := []string{"StatusError", "writeOpenAPIError"}
+	if a.HasParams() {
+		ids = append(ids, "parseOpenAPIParam")
+	}
+	for _, p := range a.Routes {
+		ids = append(ids, p.Handler)
+		for _, o := range p.Endpoints {
+			ids = append(ids, "serve"+o.Name)
+		}
+	}
+	return ids
+}
+
+//builder builds the API from the document
+type builder struct {
+	//doc is the document from which the API is built
+	doc *Document
+	//types are the types declared in the api package by their name
+	types map[string]*Type
+	//idents are the identifiers declared in the routes package
+	idents map[string]bool
+	//combining are the schemas in the components whose properties are being combined through allOf
+	combining map[string]bool
+}
+
+//API returns the code to be generated for the document
+func (d *Document) API() (*API, error) {
+	/*
+	 * We will get the version of the routes from the servers
+	 * We will declare the types of the schemas in the components
+	 * Then we will add the paths with their operations in the order of their pattern
+	 */
+	b := &builder{doc: d, types: map[string]*Type{}, idents: map[string]bool{}, combining: map[string]bool{}}
+	a := &API{Title: d.Info.Title, Version: DefaultVersion}
+	if len(a.Title) == 0 {
+		a.Title = "the API"
+	}
+	if len(d.Servers) > 0 {
+		u, err := url.Parse(d.Servers[0].URL)
+		if err == nil {
+			segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+			if v := segments[len(segments)-1]; versionSegment.MatchString(v) {
+				a.Version = v
+			}
+		}
+	}
+
+	//declaring the types of the schemas
+	for _, name := range sortedKeys(d.Components.Schemas) {
+		b.combining[name] = true
+		_, err := b.declare(name, d.Components.Schemas[name], []string{generate.PascalCase(name) + " is the " + label(name) + " schema of " + a.Title})
+		delete(b.combining, name)
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: %v", name, err)
+		}
+	}
+
+	//adding the paths
+	paths := []string{}
+	for k := range d.Paths {
+		paths = append(paths, k)
+	}
+	sort.Strings(paths)
+	for _, v := range paths {
+		p, err := b.route(v, d.Paths[v])
+		if err != nil {
+			return nil, fmt.Errorf("path %s: %v", v, err)
+		}
+		if len(p.Endpoints) > 0 {
+			a.Routes = append(a.Routes, p)
+		}
+	}
+	for _, t := range b.types {
+		a.Types = append(a.Types, t)
+	}
+	sort.Slice(a.Types, func(i, j int) bool { return a.Types[i].Name < a.Types[j].Name })
+	return a, nil
+}
+
+//route builds the route of the path with its operations
+func (b *builder) route(path string, item *PathItem) (*Route, error) {
+	/*
+	 * We will convert the path parameters to go identifiers in the pattern
+	 * Then we will build the operations of each method
+	 */
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("path has to start with a /")
+	}
+	keys := map[string]string{}
+	var err error
+	pattern := pathParam.ReplaceAllStringFunc(path, func(s string) string {
+		name := strings.Trim(s, "{}")
+		key := generate.CamelCase(name)
+		if !token.IsIdentifier(key) {
+			err = fmt.Errorf("path parameter %s isn't a valid name", name)
+		}
+		keys[name] = key
+		return "{" + key + "}"
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range strings.Split(pattern, "/") {
+		if strings.ContainsAny(v, "{}") && !segmentParam.MatchString(v) {
+			return nil, fmt.Errorf("path parameters have to be whole segments of the path like /users/{id}")
+		}
+	}
+	if strings.ContainsAny(pattern, "\"` \t\n") {
+		return nil, fmt.Errorf("path isn't a valid pattern")
+	}
+	handler := generate.CamelCase(path) + "Route"
+	if path == "/" {
+		handler = "rootRoute"
+	}
+	err = b.ident(handler)
+	if err != nil {
+		return nil, err
+	}
+	p := &Route{Path: path, Pattern: pattern, Handler: handler}
+	if item == nil {
+		return p, nil
+	}
+
+	//building the operations
+	for _, m := range Methods {
+		op := item.Operation(m)
+		if op == nil {
+			if len(p.OtherMethod) == 0 {
+				p.OtherMethod = methodConsts[m]
+			}
+			continue
+		}
+		o, err := b.endpoint(path, m, keys, item.Parameters, op)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", m, err)
+		}
+		p.Endpoints = append(p.Endpoints, o)
+	}
+	return p, nil
+}
+
+//endpoint builds the endpoint of the operation with its parameters, request body and response
+func (b *builder) endpoint(path, method string, keys map[string]string, common []*Parameter, op *Operation) (*Endpoint, error) {
+	/*
+	 * We will name the operation
+	 * We will add the parameters of the path and the operation
+	 * We will add the json request body
+	 * Then we will add the json response of the first success status or of the default response if it is the only one
+	 */
+	name := generate.PascalCase(op.OperationID)
+	if len(name) == 0 {
+		name = generate.PascalCase(strings.ToLower(method) + " " + path)
+		if path == "/" {
+			name += "Root"
+		}
+	}
+	if !token.IsIdentifier(name) {
+		return nil, fmt.Errorf("%q isn't a valid operation name", op.OperationID)
+	}
+	err := b.ident(name)
+	if err == nil {
+		err = b.ident("serve" + name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	o := &Endpoint{Name: name, Method: method, MethodConst: methodConsts[method], Path: path, Status: "http.StatusOK"}
+	o.Doc = append(lines(op.Summary), lines(op.Description)...)
+
+	//adding the parameters
+	err = b.params(o, keys, append(append([]*Parameter{}, common...), op.Parameters...))
+	if err != nil {
+		return nil, err
+	}
+
+	//adding the request body
+	if op.RequestBody != nil {
+		body, err := b.doc.requestBody(op.RequestBody)
+		if err != nil {
+			return nil, err
+		}
+		o.Body, err = b.value(body.Content, name+"Request", []string{name + "Request is the request body of " + name})
+		if err != nil {
+			return nil, fmt.Errorf("request body: %v", err)
+		}
+	}
+
+	//adding the response. The default response is usually the error, so it is taken only if there is no other response
+	codes := []string{}
+	for k := range op.Responses {
+		codes = append(codes, k)
+	}
+	sort.Strings(codes)
+	for _, k := range codes {
+		res := op.Responses[k]
+		if !strings.HasPrefix(k, "2") && !(k == "default" && len(codes) == 1) {
+			continue
+		}
+		if c, ok := statusConsts[k]; ok {
+			o.Status = "http." + c
+		}
+		res, err = b.doc.response(res)
+		if err != nil {
+			return nil, err
+		}
+		if k == "204" {
+			break
+		}
+		o.Response, err = b.value(res.Content, name+"Response", []string{name + "Response is the response of " + name})
+		if err != nil {
+			return nil, fmt.Errorf("response %s: %v", k, err)
+		}
+		break
+	}
+	return o, nil
+}
+
+//params adds the parameters to the operation and declares the type of its parameters.
+//The parameters of the operation override the parameters of the path with the same name and location.
+func (b *builder) params(o *Endpoint, keys map[string]string, params []*Parameter) error {
+	byName := map[string]*Parameter{}
+	order := []string{}
+	for _, v := range params {
+		p, err := b.doc.parameter(v)
+		if err != nil {
+			return err
+		}
+		k := p.In + " " + p.Name
+		if _, ok := byName[k]; !ok {
+			order = append(order, k)
+		}
+		byName[k] = p
+	}
+	if len(order) == 0 {
+		return nil
+	}
+	t := &Type{Name: o.Name + "Params", Doc: []string{o.Name + "Params are the parameters of " + o.Name}}
+	fields := map[string]bool{}
+	for _, k := range order {
+		p := byName[k]
+		param := &Param{Name: p.Name, Field: generate.PascalCase(p.Name), In: p.In, Key: p.Name, Required: p.Required}
+		switch p.In {
+		case "path":
+			key, ok := keys[p.Name]
+			if !ok {
+				return fmt.Errorf("path parameter %s isn't in the path", p.Name)
+			}
+			param.Key, param.Required = key, true
+		case "query", "header":
+		default:
+			return fmt.Errorf("parameter %s in %s isn't supported. Only the path, query and header parameters are supported", p.Name, p.In)
+		}
+		if !token.IsIdentifier(param.Field) || fields[param.Field] {
+			return fmt.Errorf("parameter %s can't be a field of %s", p.Name, t.Name)
+		}
+		fields[param.Field] = true
+		typ, err := b.goType(p.Schema, "", nil)
+		if err != nil {
+			return err
+		}
+		if !paramTypes[typ] {
+			return fmt.Errorf("type %s of the parameter %s isn't supported. Only strings, numbers, booleans and arrays of strings are supported", typ, p.Name)
+		}
+		doc := lines(p.Description)
+		if len(doc) == 0 {
+			doc = []string{param.Field + " is the " + p.In + " parameter " + p.Name}
+		}
+		t.Fields = append(t.Fields, &Field{Name: param.Field, Type: typ, Doc: doc})
+		o.Params = append(o.Params, param)
+	}
+	err := b.add(t)
+	if err != nil {
+		return err
+	}
+	o.ParamsType = Package + "." + t.Name
+	return nil
+}
+
+//value returns the json body of the given content. The inline object schemas are declared with the given name.
+//It returns nil if the content has no json media type.
+func (b *builder) value(content map[string]*MediaType, name string, doc []string) (*Value, error) {
+	var media *MediaType
+	for _, k := range sortedKeys(content) {
+		if strings.Contains(k, "json") {
+			media = content[k]
+			break
+		}
+	}
+	if media == nil {
+		return nil, nil
+	}
+	typ, err := b.goType(media.Schema, name, doc)
+	if err != nil {
+		return nil, err
+	}
+	typ = exportedIdent.ReplaceAllString(typ, "${1}"+Package+".${2}")
+	if strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") || typ == "json.RawMessage" {
+		return &Value{Type: typ}, nil
+	}
+	return &Value{Type: "*" + typ, Elem: typ, Ptr: true}, nil
+}
+
+//declare declares the named type of the given schema in the api package
+func (b *builder) declare(name string, s *Schema, doc []string) (*Type, error) {
+	t := &Type{Name: generate.PascalCase(name), Doc: append(doc, lines(s.Description)...)}
+	if !token.IsIdentifier(t.Name) {
+		return nil, fmt.Errorf("%q isn't a valid type name", name)
+	}
+	if len(s.Ref) > 0 {
+		_, err := b.schema(s)
+		if err != nil {
+			return nil, err
+		}
+		typ, err := b.goType(s, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		t.Underlying, t.Alias = typ, true
+		return t, b.add(t)
+	}
+	props, required, embeds, ok, err := b.properties(s)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		typ, err := b.goType(s, t.Name+"Value", nil)
+		if err != nil {
+			return nil, err
+		}
+		t.Underlying = typ
+		return t, b.add(t)
+	}
+	err = b.add(t)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]bool{}
+	for _, v := range embeds {
+		if fields[v] {
+			return nil, fmt.Errorf("%s is combined more than once in %s", v, t.Name)
+		}
+		fields[v] = true
+		t.Fields = append(t.Fields, &Field{Name: v, Type: v, Embedded: true, Doc: []string{v + " has the properties of the " + label(v)}})
+	}
+	for _, p := range props {
+		f := &Field{Name: generate.PascalCase(p.Name), Tag: `json:"` + p.Name + `"`, Doc: lines(p.Schema.Description)}
+		if !required[p.Name] {
+			f.Tag = `json:"` + p.Name + `,omitempty"`
+		}
+		if !token.IsIdentifier(f.Name) || fields[f.Name] {
+			return nil, fmt.Errorf("property %s can't be a field of %s", p.Name, t.Name)
+		}
+		fields[f.Name] = true
+		if len(f.Doc) == 0 {
+			f.Doc = []string{f.Name + " of the " + label(t.Name)}
+		}
+		f.Type, err = b.goType(p.Schema, t.Name+f.Name, []string{t.Name + f.Name + " is the " + label(p.Name) + " of the " + label(t.Name)})
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %v", p.Name, err)
+		}
+		t.Fields = append(t.Fields, f)
+	}
+	return t, nil
+}
+
+//properties returns the properties of the object schema combining the ones in its inline allOf schemas
+//along with the names of the types of the schemas referred in its allOf to be embedded.
+//It returns false if the schema isn't an object with properties. It returns an error if a schema is combined in itself.
+func (b *builder) properties(s *Schema) (Properties, map[string]bool, []string, bool, error) {
+	required := map[string]bool{}
+	for _, v := range s.Required {
+		required[v] = true
+	}
+	props := append(Properties{}, s.Properties...)
+	embeds := []string{}
+	ok := len(props) > 0 || len(s.AllOf) > 0
+	for _, v := range s.AllOf {
+		r, err := b.schema(v)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+		name := ""
+		if len(v.Ref) > 0 {
+			name, _ = refName(v.Ref, "schemas")
+			if b.combining[name] {
+				return nil, nil, nil, false, fmt.Errorf("schema %s is combined in itself through allOf", v.Ref)
+			}
+			b.combining[name] = true
+		}
+		p, req, e, isObject, err := b.properties(r)
+		delete(b.combining, name)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+		if !isObject && !r.Type.Is("object") {
+			return nil, nil, nil, false, fmt.Errorf("only the objects can be combined with allOf")
+		}
+		if len(v.Ref) > 0 {
+			embeds = append(embeds, generate.PascalCase(name))
+			continue
+		}
+		props = append(props, p...)
+		embeds = append(embeds, e...)
+		for k := range req {
+			required[k] = true
+		}
+	}
+	return props, required, embeds, ok, nil
+}
+
+//schema resolves the reference of the given schema following the references of the schemas referred.
+//It returns an error if the references refer to a schema again.
+func (b *builder) schema(s *Schema) (*Schema, error) {
+	seen := map[string]bool{}
+	for len(s.Ref) > 0 {
+		name, err := resolving(s.Ref, "schemas", seen)
+		if err != nil {
+			return nil, err
+		}
+		r, ok := b.doc.Components.Schemas[name]
+		if !ok {
+			return nil, fmt.Errorf("schema %s isn't in the components", s.Ref)
+		}
+		s = r
+	}
+	return s, nil
+}
+
+//goType returns the go type of the schema. The inline object schemas are declared with the given name and doc.
+func (b *builder) goType(s *Schema, name string, doc []string) (string, error) {
+	if s == nil || len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		return "json.RawMessage", nil
+	}
+	if len(s.Ref) > 0 {
+		ref, err := refName(s.Ref, "schemas")
+		if err != nil {
+			return "", err
+		}
+		if _, ok := b.doc.Components.Schemas[ref]; !ok {
+			return "", fmt.Errorf("schema %s isn't in the components", s.Ref)
+		}
+		return generate.PascalCase(ref), nil
+	}
+	if len(s.AllOf) == 1 && len(s.Properties) == 0 {
+		return b.goType(s.AllOf[0], name, doc)
+	}
+	if len(s.Properties) > 0 || len(s.AllOf) > 0 {
+		if len(name) == 0 {
+			return "", fmt.Errorf("inline objects aren't supported here")
+		}
+		t, err := b.declare(name, s, doc)
+		if err != nil {
+			return "", err
+		}
+		return t.Name, nil
+	}
+	switch {
+	case s.Type.Is("object"):
+		if s.AdditionalProperties == nil || s.AdditionalProperties.Schema == nil {
+			return "map[string]interface{}", nil
+		}
+		typ, err := b.goType(s.AdditionalProperties.Schema, child(name, "Value"), doc)
+		return "map[string]" + typ, err
+	case s.Type.Is("array"):
+		typ, err := b.goType(s.Items, child(name, "Item"), doc)
+		return "[]" + typ, err
+	case s.Type.Is("string"):
+		switch s.Format {
+		case "date-time":
+			return "time.Time", nil
+		case "byte":
+			return "[]byte", nil
+		}
+		return "string", nil
+	case s.Type.Is("integer"):
+		if s.Format == "int32" {
+			return "int32", nil
+		}
+		return "int64", nil
+	case s.Type.Is("number"):
+		if s.Format == "float" {
+			return "float32", nil
+		}
+		return "float64", nil
+	case s.Type.Is("boolean"):
+		return "bool", nil
+	}
+	return "json.RawMessage", nil
+}
+
+//add adds the type to the api package
+func (b *builder) add(t *Type) error {
+	if _, ok := b.types[t.Name]; ok {
+		return fmt.Errorf("type %s is declared more than once", t.Name)
+	}
+	b.types[t.Name] = t
+	return nil
+}
+
+//ident adds the identifier to the routes package
+func (b *builder) ident(name string) error {
+	if b.idents[name] {
+		return fmt.Errorf("%s is declared more than once", name)
+	}
+	b.idents[name] = true
+	return nil
+}
+
+//child returns the name of the inline schema in the schema of the given name. It is empty if the name is empty
+func child(name, suffix string) string {
+	if len(name) == 0 {
+		return name
+	}
+	return name + suffix
+}
+
+//sortedKeys returns the keys of the map sorted
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+	switch v := m.(type) {
+	case map[string]*Schema:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]*MediaType:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+//lines splits the text into its non empty lines
+func lines(s string) []string {
+	ls := []string{}
+	for _, v := range strings.Split(s, "\n") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			ls = append(ls, v)
+		}
+	}
+	return ls
+}
+
+//label returns the name in words to be used in the comments like order item
+func label(name string) string {
+	return strings.Join(generate.Words(name), " ")
+}
@@ -0,0 +1,190 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package openapi_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/openapi"
+)
+
+/*
+ * This file contains the tests for the source code of api.go
+ */
+
+//TestPetStoreAPI checks the code generated for the pet store document in testdata
+func TestPetStoreAPI(t *testing.T) {
+	d, err := openapi.Load(filepath.Join("testdata", "petstore.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := d.API()
+	if err != nil {
+		t.Fatal("Didn't expect an error. Got one", err)
+	}
+	if a.Version != "v2" {
+		t.Error("Expected the version from the server url v2. Got", a.Version)
+	}
+
+	//checking the types
+	types := map[string]*openapi.Type{}
+	names := []string{}
+	for _, v := range a.Types {
+		types[v.Name] = v
+		names = append(names, v.Name)
+	}
+	expected := []string{"Animal", "DeletePetParams", "GetPetParams", "ListPetsParams", "NewPet", "NewPetOwner", "Pet", "Pets",
+		"UpdatePetParams", "UpdatePetRequest", "UpdatePetResponse"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Error("Expected the types", expected, "Got", names)
+	}
+	if p := types["Pet"]; p == nil || len(p.Fields) == 0 || !p.Fields[0].Embedded || p.Fields[0].Type != "NewPet" {
+		t.Error("Expected Pet to embed NewPet combined with allOf")
+	}
+	if a := types["Animal"]; a == nil || !a.Alias || a.Underlying != "Pet" {
+		t.Error("Expected Animal to be an alias of Pet")
+	}
+	if p := types["Pets"]; p == nil || p.Underlying != "[]Pet" {
+		t.Error("Expected Pets to be a slice of Pet")
+	}
+
+	//checking the routes
+	patterns := []string{}
+	for _, v := range a.Routes {
+		patterns = append(patterns, v.Pattern)
+	}
+	if !reflect.DeepEqual(patterns, []string{"/health", "/pets", "/pets/{petId}"}) {
+		t.Error("Expected the patterns sorted by their path with the parameters as identifiers. Got", patterns)
+	}
+}
+
+var endpointtcs = []struct {
+	Name      string
+	Path      string
+	Method    string
+	Signature string
+	Status    string
+}{
+	{"Operation without an id", "/health", "GET", "func(context.Context, *http.Request) error", "http.StatusOK"},
+	{"Query parameters and a slice response", "/pets", "GET", "func(context.Context, *http.Request, api.ListPetsParams) ([]api.Pet, error)", "http.StatusOK"},
+	{"Referred body and response", "/pets", "POST", "func(context.Context, *http.Request, *api.NewPet) (*api.Pet, error)", "http.StatusCreated"},
+	{"Inline body and response", "/pets/{pet-id}", "PUT", "func(context.Context, *http.Request, api.UpdatePetParams, *api.UpdatePetRequest) (*api.UpdatePetResponse, error)", "http.StatusOK"},
+	{"No content", "/pets/{pet-id}", "DELETE", "func(context.Context, *http.Request, api.DeletePetParams) error", "http.StatusNoContent"},
+}
+
+//TestEndpoints checks the stubs of the operations of the pet store document in testdata
+func TestEndpoints(t *testing.T) {
+	d, err := openapi.Load(filepath.Join("testdata", "petstore.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := d.API()
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoints := map[string]*openapi.Endpoint{}
+	for _, r := range a.Routes {
+		for _, e := range r.Endpoints {
+			endpoints[e.Method+" "+e.Path] = e
+		}
+	}
+	for _, v := range endpointtcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			e, ok := endpoints[v.Method+" "+v.Path]
+			if !ok {
+				t.Fatal("Expected the endpoint", v.Method, v.Path)
+			}
+			if e.Signature() != v.Signature {
+				t.Error("Expected the signature", v.Signature, "Got", e.Signature())
+			}
+			if e.Status != v.Status {
+				t.Error("Expected the status", v.Status, "Got", e.Status)
+			}
+		})
+	}
+}
+
+var responsetcs = []struct {
+	Name      string
+	Responses string
+	Signature string
+}{
+	{
+		"Default error without a success response",
+		"        '404': {description: Missing}\n        default: {description: Error, content: {application/json: {schema: {properties: {message: {type: string}}}}}}\n",
+		"func(context.Context, *http.Request) error",
+	},
+	{
+		"Default error with a success response",
+		"        '200': {description: Name, content: {application/json: {schema: {type: string}}}}\n        default: {description: Error, content: {application/json: {schema: {properties: {message: {type: string}}}}}}\n",
+		"func(context.Context, *http.Request) (*string, error)",
+	},
+	{
+		"Only the default response",
+		"        default: {description: Name, content: {application/json: {schema: {properties: {name: {type: string}}}}}}\n",
+		"func(context.Context, *http.Request) (*api.GetNameResponse, error)",
+	},
+}
+
+//TestResponses checks that the default response is taken as the response of an operation only if it is its only response
+func TestResponses(t *testing.T) {
+	for _, v := range responsetcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			d, err := openapi.Parse([]byte("openapi: 3.0.0\npaths:\n  /name:\n    get:\n      operationId: getName\n      responses:\n" + v.Responses))
+			if err != nil {
+				t.Fatal(err)
+			}
+			a, err := d.API()
+			if err != nil {
+				t.Fatal("Didn't expect an error. Got one", err)
+			}
+			if s := a.Routes[0].Endpoints[0].Signature(); s != v.Signature {
+				t.Error("Expected the signature", v.Signature, "Got", s)
+			}
+		})
+	}
+}
+
+var apierrortcs = []struct {
+	Name string
+	Doc  string
+}{
+	{"Cookie parameter", "openapi: 3.0.0\npaths:\n  /a:\n    get:\n      parameters:\n        - {name: s, in: cookie, schema: {type: string}}\n"},
+	{"Parameter not in the path", "openapi: 3.0.0\npaths:\n  /a:\n    get:\n      parameters:\n        - {name: id, in: path, schema: {type: string}}\n"},
+	{"Parameter in a part of a segment", "openapi: 3.0.0\npaths:\n  /files/{name}.json:\n    get: {}\n"},
+	{"Object parameter", "openapi: 3.0.0\npaths:\n  /a:\n    get:\n      parameters:\n        - {name: f, in: query, schema: {type: object}}\n"},
+	{"Duplicate operation ids", "openapi: 3.0.0\npaths:\n  /a:\n    get: {operationId: list}\n  /b:\n    get: {operationId: list}\n"},
+	{"Reference to another document", "openapi: 3.0.0\ncomponents:\n  schemas:\n    A: {$ref: 'other.yaml#/components/schemas/B'}\n"},
+	{"Schemas combined in each other", "openapi: 3.0.0\ncomponents:\n  schemas:\n    A: {allOf: [{$ref: '#/components/schemas/B'}]}\n    B: {allOf: [{$ref: '#/components/schemas/A'}]}\n"},
+	{"Schema combined in itself", "openapi: 3.0.0\ncomponents:\n  schemas:\n    A: {allOf: [{$ref: '#/components/schemas/A'}, {properties: {id: {type: string}}}]}\n"},
+	{"Schema combined in itself through an inline object", "openapi: 3.0.0\ncomponents:\n  schemas:\n    A:\n      properties:\n        b: {allOf: [{$ref: '#/components/schemas/A'}], properties: {id: {type: string}}}\n      allOf: [{$ref: '#/components/schemas/C'}]\n    C: {allOf: [{$ref: '#/components/schemas/A'}]}\n"},
+	{"Schemas referring to each other", "openapi: 3.0.0\ncomponents:\n  schemas:\n    A: {$ref: '#/components/schemas/B'}\n    B: {$ref: '#/components/schemas/A'}\n"},
+	{"Parameters referring to each other", "openapi: 3.0.0\ncomponents:\n  parameters:\n    p: {$ref: '#/components/parameters/q'}\n    q: {$ref: '#/components/parameters/p'}\npaths:\n  /a:\n    get:\n      parameters:\n        - $ref: '#/components/parameters/p'\n"},
+	{"Response referring to itself", "openapi: 3.0.0\ncomponents:\n  responses:\n    r: {$ref: '#/components/responses/r'}\npaths:\n  /a:\n    get:\n      responses:\n        '200': {$ref: '#/components/responses/r'}\n"},
+	{"Missing schema", "openapi: 3.0.0\ncomponents:\n  schemas:\n    A: {$ref: '#/components/schemas/B'}\n"},
+	{"Clashing type names", "openapi: 3.0.0\ncomponents:\n  schemas:\n    ListParams: {type: string}\npaths:\n  /a:\n    get:\n      operationId: list\n      parameters:\n        - {name: q, in: query, schema: {type: string}}\n"},
+}
+
+//TestAPIErrors checks that the documents whose code can't be generated are rejected
+func TestAPIErrors(t *testing.T) {
+	for _, v := range apierrortcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			d, err := openapi.Parse([]byte(v.Doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = d.API()
+			if err == nil {
+				t.Error("Expected an error. Got none.")
+			}
+		})
+	}
+}
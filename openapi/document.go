@@ -0,0 +1,368 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+/*
+ * This file contains the definitions of the OpenAPI 3 document and its loading.
+ * Only the parts of the document required for generating the code of the routes are read.
+ */
+
+//Methods are the http methods of the operations in a path item in the order in which they are generated
+var Methods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+
+//Document is an OpenAPI 3 document in yaml or json
+type Document struct {
+	//OpenAPI is the version of the OpenAPI specification of the document like 3.0.3
+	OpenAPI string `yaml:"openapi"`
+	//Info has the title and the version of the API
+	Info Info `yaml:"info"`
+	//Servers are the servers of the API. The version of the routes is taken from the url of the first one
+	Servers []Server `yaml:"servers"`
+	//Paths are the path items of the API with their url pattern
+	Paths map[string]*PathItem `yaml:"paths"`
+	//Components are the reusable schemas, parameters, request bodies and responses of the API
+	Components Components `yaml:"components"`
+}
+
+//Info is the metadata of the API
+type Info struct {
+	//Title of the API
+	Title string `yaml:"title"`
+	//Version of the API document
+	Version string `yaml:"version"`
+}
+
+//Server is a server of the API
+type Server struct {
+	//URL of the server like https://api.example.com/v1
+	URL string `yaml:"url"`
+}
+
+//PathItem has the operations of a path
+type PathItem struct {
+	//Parameters are the parameters common to all the operations of the path
+	Parameters []*Parameter `yaml:"parameters"`
+	//Get is the GET operation of the path
+	Get *Operation `yaml:"get"`
+	//Head is the HEAD operation of the path
+	Head *Operation `yaml:"head"`
+	//Post is the POST operation of the path
+	Post *Operation `yaml:"post"`
+	//Put is the PUT operation of the path
+	Put *Operation `yaml:"put"`
+	//Patch is the PATCH operation of the path
+	Patch *Operation `yaml:"patch"`
+	//Delete is the DELETE operation of the path
+	Delete *Operation `yaml:"delete"`
+	//Options is the OPTIONS operation of the path
+	Options *Operation `yaml:"options"`
+}
+
+//Operation returns the operation of the given http method in the path item. It returns nil if there is none.
+func (p PathItem) Operation(method string) *Operation {
+	switch method {
+	case "GET":
+		return p.Get
+	case "HEAD":
+		return p.Head
+	case "POST":
+		return p.Post
+	case "PUT":
+		return p.Put
+	case "PATCH":
+		return p.Patch
+	case "DELETE":
+		return p.Delete
+	case "OPTIONS":
+		return p.Options
+	}
+	return nil
+}
+
+//Operation is an operation of the API on a path
+type Operation struct {
+	//OperationID is the unique name of the operation. It is the name of its handler
+	OperationID string `yaml:"operationId"`
+	//Summary of the operation
+	Summary string `yaml:"summary"`
+	//Description of the operation
+	Description string `yaml:"description"`
+	//Parameters of the operation. They override the parameters of the path with the same name and location
+	Parameters []*Parameter `yaml:"parameters"`
+	//RequestBody is the body of the requests of the operation
+	RequestBody *RequestBody `yaml:"requestBody"`
+	//Responses are the responses of the operation by their status code
+	Responses map[string]*Response `yaml:"responses"`
+}
+
+//Parameter is a path, query or header parameter of an operation
+type Parameter struct {
+	//Ref is the reference to a parameter in the components like #/components/parameters/limit
+	Ref string `yaml:"$ref"`
+	//Name of the parameter
+	Name string `yaml:"name"`
+	//In is the location of the parameter. Any of path, query, header or cookie
+	In string `yaml:"in"`
+	//Description of the parameter
+	Description string `yaml:"description"`
+	//Required tells whether the parameter is required. Path parameters are always required
+	Required bool `yaml:"required"`
+	//Schema of the parameter
+	Schema *Schema `yaml:"schema"`
+}
+
+//RequestBody is the body of the requests of an operation
+type RequestBody struct {
+	//Ref is the reference to a request body in the components
+	Ref string `yaml:"$ref"`
+	//Description of the request body
+	Description string `yaml:"description"`
+	//Content has the schema of the body by its media type
+	Content map[string]*MediaType `yaml:"content"`
+}
+
+//Response is a response of an operation
+type Response struct {
+	//Ref is the reference to a response in the components
+	Ref string `yaml:"$ref"`
+	//Description of the response
+	Description string `yaml:"description"`
+	//Content has the schema of the response by its media type
+	Content map[string]*MediaType `yaml:"content"`
+}
+
+//MediaType has the schema of a request or response body of a media type
+type MediaType struct {
+	//Schema of the body
+	Schema *Schema `yaml:"schema"`
+}
+
+//Schema is the schema of a value
+type Schema struct {
+	//Ref is the reference to a schema in the components like #/components/schemas/User
+	Ref string `yaml:"$ref"`
+	//Type of the value. OpenAPI 3.1 allows a list of types like [string, "null"]
+	Type Types `yaml:"type"`
+	//Format of the value like int32 or date-time
+	Format string `yaml:"format"`
+	//Description of the value
+	Description string `yaml:"description"`
+	//Items is the schema of the items of an array
+	Items *Schema `yaml:"items"`
+	//Properties are the properties of an object in the order in which they are given
+	Properties Properties `yaml:"properties"`
+	//Required are the required properties of an object
+	Required []string `yaml:"required"`
+	//AdditionalProperties is the schema of the values of an object used as a map
+	AdditionalProperties *AdditionalProperties `yaml:"additionalProperties"`
+	//AllOf are the schemas whose properties are combined in the value
+	AllOf []*Schema `yaml:"allOf"`
+	//OneOf are the schemas one of which the value is
+	OneOf []*Schema `yaml:"oneOf"`
+	//AnyOf are the schemas any of which the value is
+	AnyOf []*Schema `yaml:"anyOf"`
+}
+
+//Types is the type of a schema given as a string or a list of strings
+type Types []string
+
+//UnmarshalYAML reads the type given as a string or a list of strings
+func (t *Types) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*t = Types{value.Value}
+		return nil
+	}
+	s := []string{}
+	err := value.Decode(&s)
+	*t = s
+	return err
+}
+
+//Is tells whether the given type is one of the types
+func (t Types) Is(name string) bool {
+	for _, v := range t {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+//Property is a property of an object schema
+type Property struct {
+	//Name of the property
+	Name string
+	//Schema of the property
+	Schema *Schema
+}
+
+//Properties are the properties of an object schema in the order in which they are given in the document
+type Properties []Property
+
+//UnmarshalYAML reads the properties keeping their order in the document
+func (p *Properties) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: properties have to be a map", value.Line)
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		s := &Schema{}
+		err := value.Content[i+1].Decode(s)
+		if err != nil {
+			return err
+		}
+		*p = append(*p, Property{Name: value.Content[i].Value, Schema: s})
+	}
+	return nil
+}
+
+//AdditionalProperties is the additionalProperties of an object schema given as a boolean or a schema
+type AdditionalProperties struct {
+	//Allowed tells whether the object can have properties other than the ones given
+	Allowed bool
+	//Schema is the schema of the additional properties
+	Schema *Schema
+}
+
+//UnmarshalYAML reads the additional properties given as a boolean or a schema
+func (a *AdditionalProperties) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&a.Allowed)
+	}
+	a.Allowed, a.Schema = true, &Schema{}
+	return value.Decode(a.Schema)
+}
+
+//Components are the reusable parts of the document
+type Components struct {
+	//Schemas are the named schemas of the API
+	Schemas map[string]*Schema `yaml:"schemas"`
+	//Parameters are the reusable parameters
+	Parameters map[string]*Parameter `yaml:"parameters"`
+	//RequestBodies are the reusable request bodies
+	RequestBodies map[string]*RequestBody `yaml:"requestBodies"`
+	//Responses are the reusable responses
+	Responses map[string]*Response `yaml:"responses"`
+}
+
+//Load reads the OpenAPI 3 document from the given yaml or json file
+func Load(file string) (*Document, error) {
+	/*
+	 * We will read the file
+	 * Then we will decode the document
+	 * Then we will check that it is an OpenAPI 3 document
+	 */
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		//error while reading the document
+		fmt.Println("Error while reading the OpenAPI document", file)
+		return nil, err
+	}
+	d, err := Parse(b)
+	if err != nil {
+		//error while parsing the document
+		fmt.Println("Error while parsing the OpenAPI document", file)
+		return nil, err
+	}
+	return d, nil
+}
+
+//Parse decodes the OpenAPI 3 document given in yaml or json
+func Parse(b []byte) (*Document, error) {
+	d := &Document{}
+	err := yaml.Unmarshal(b, d)
+	if err != nil {
+		return nil, err
+	}
+	if len(d.OpenAPI) == 0 {
+		return nil, errors.New("the document has no openapi version. Only OpenAPI 3 documents are supported")
+	}
+	if !strings.HasPrefix(d.OpenAPI, "3.") {
+		return nil, fmt.Errorf("OpenAPI %s isn't supported. Only OpenAPI 3 documents are supported", d.OpenAPI)
+	}
+	return d, nil
+}
+
+//refName returns the name of the component the reference points to in the given section of the components
+func refName(ref, section string) (string, error) {
+	prefix := "#/components/" + section + "/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "", fmt.Errorf("reference %s isn't supported. Only the references to %s in the same document are supported", ref, prefix)
+	}
+	return strings.TrimPrefix(ref, prefix), nil
+}
+
+//parameter resolves the reference of the given parameter following the references of the parameters referred
+func (d *Document) parameter(p *Parameter) (*Parameter, error) {
+	seen := map[string]bool{}
+	for len(p.Ref) > 0 {
+		name, err := resolving(p.Ref, "parameters", seen)
+		if err != nil {
+			return nil, err
+		}
+		r, ok := d.Components.Parameters[name]
+		if !ok {
+			return nil, fmt.Errorf("parameter %s isn't in the components", p.Ref)
+		}
+		p = r
+	}
+	return p, nil
+}
+
+//requestBody resolves the reference of the given request body following the references of the request bodies referred
+func (d *Document) requestBody(b *RequestBody) (*RequestBody, error) {
+	seen := map[string]bool{}
+	for len(b.Ref) > 0 {
+		name, err := resolving(b.Ref, "requestBodies", seen)
+		if err != nil {
+			return nil, err
+		}
+		r, ok := d.Components.RequestBodies[name]
+		if !ok {
+			return nil, fmt.Errorf("request body %s isn't in the components", b.Ref)
+		}
+		b = r
+	}
+	return b, nil
+}
+
+//response resolves the reference of the given response following the references of the responses referred
+func (d *Document) response(res *Response) (*Response, error) {
+	seen := map[string]bool{}
+	for len(res.Ref) > 0 {
+		name, err := resolving(res.Ref, "responses", seen)
+		if err != nil {
+			return nil, err
+		}
+		r, ok := d.Components.Responses[name]
+		if !ok {
+			return nil, fmt.Errorf("response %s isn't in the components", res.Ref)
+		}
+		res = r
+	}
+	return res, nil
+}
+
+//resolving returns the name of the component the reference points to in the given section and records it in the
+//components seen while resolving a reference. It returns an error if the component was already seen.
+func resolving(ref, section string, seen map[string]bool) (string, error) {
+	name, err := refName(ref, section)
+	if err != nil {
+		return "", err
+	}
+	if seen[name] {
+		return "", fmt.Errorf("reference %s refers to itself through the references of the %s", ref, section)
+	}
+	seen[name] = true
+	return name, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package openapi_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/openapi"
+)
+
+/*
+ * This file contains the tests for the source code of document.go
+ */
+
+var parsetcs = []struct {
+	Name  string
+	Doc   string
+	Check func(d *openapi.Document) error
+	Error bool
+}{
+	{
+		"Properties keep their order",
+		"openapi: 3.0.3\ncomponents:\n  schemas:\n    User:\n      properties:\n        name: {type: string}\n        age: {type: integer}\n        email: {type: string}\n",
+		func(d *openapi.Document) error {
+			names := []string{}
+			for _, v := range d.Components.Schemas["User"].Properties {
+				names = append(names, v.Name)
+			}
+			if !reflect.DeepEqual(names, []string{"name", "age", "email"}) {
+				return fmt.Errorf("expected the properties in order. Got %v", names)
+			}
+			return nil
+		},
+		false,
+	},
+	{
+		"List of types in OpenAPI 3.1",
+		`{"openapi": "3.1.0", "components": {"schemas": {"Name": {"type": ["string", "null"]}}}}`,
+		func(d *openapi.Document) error {
+			if !d.Components.Schemas["Name"].Type.Is("string") {
+				return fmt.Errorf("expected the type string. Got %v", d.Components.Schemas["Name"].Type)
+			}
+			return nil
+		},
+		false,
+	},
+	{
+		"Additional properties as a boolean and a schema",
+		"openapi: 3.0.0\ncomponents:\n  schemas:\n    A: {type: object, additionalProperties: false}\n    B: {type: object, additionalProperties: {type: integer}}\n",
+		func(d *openapi.Document) error {
+			a, b := d.Components.Schemas["A"].AdditionalProperties, d.Components.Schemas["B"].AdditionalProperties
+			if a.Allowed || !b.Allowed || b.Schema == nil || !b.Schema.Type.Is("integer") {
+				return fmt.Errorf("expected A to not allow and B to have integers. Got %v %v", a, b)
+			}
+			return nil
+		},
+		false,
+	},
+	{"Swagger 2 document", "swagger: '2.0'\n", nil, true},
+	{"Unsupported version", "openapi: 4.0.0\n", nil, true},
+	{"Invalid document", "openapi: [3\n", nil, true},
+}
+
+//TestParse is the test suite for parsing the OpenAPI documents
+func TestParse(t *testing.T) {
+	for _, v := range parsetcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			d, err := openapi.Parse([]byte(v.Doc))
+			if err == nil && v.Error {
+				t.Error("Expected an error. Got none.")
+				return
+			}
+			if err != nil && !v.Error {
+				t.Error("Didn't expect an error. Got one", err.Error())
+				return
+			}
+			if v.Check == nil {
+				return
+			}
+			err = v.Check(d)
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+//TestLoad loads the pet store document in testdata
+func TestLoad(t *testing.T) {
+	d, err := openapi.Load(filepath.Join("testdata", "petstore.yaml"))
+	if err != nil {
+		t.Fatal("Didn't expect an error. Got one", err)
+	}
+	if d.Info.Title != "Pet Store" || len(d.Paths) != 3 || d.Paths["/pets"].Post == nil {
+		t.Error("Expected the pet store with 3 paths. Got", d.Info, len(d.Paths))
+	}
+	_, err = openapi.Load(filepath.Join("testdata", "missing.yaml"))
+	if err == nil {
+		t.Error("Expected an error for a missing document. Got none.")
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+ * This file contains the definitions of the optional components of the generated project
+ */
+
+const (
+	//DatabaseComponent is the postgres database connection of the app context using gorm
+	DatabaseComponent = "database"
+	//VaultComponent is the loading of the secrets from vault as environment variables
+	VaultComponent = "vault"
+	//RateLimiterComponent is the limit on the no. of requests served at a given point of time
+	RateLimiterComponent = "ratelimiter"
+	//ExamplesComponent is the examples of the routes
+	ExamplesComponent = "examples"
+)
+
+//ComponentNames are the names of the optional components in the order they are asked for
+var ComponentNames = []string{DatabaseComponent, VaultComponent, RateLimiterComponent, ExamplesComponent}
+
+//Components are the optional components of the generated project. The template includes the files and
+//imports of a component only if it is chosen, so that the project depends only on what it uses.
+type Components struct {
+	//Database connects the app context to a postgres database
+	Database bool `json:"database" yaml:"database"`
+	//Vault loads the secrets from vault as environment variables
+	Vault bool `json:"vault" yaml:"vault"`
+	//RateLimiter limits the no. of requests served at a given point of time
+	RateLimiter bool `json:"rateLimiter" yaml:"rateLimiter"`
+	//Examples has the examples of the routes
+	Examples bool `json:"examples" yaml:"examples"`
+}
+
+//AllComponents returns the components with all of them chosen. It is the default for the projects
+//which didn't choose them, like the ones generated before the components could be chosen.
+func AllComponents() *Components {
+	return &Components{Database: true, Vault: true, RateLimiter: true, Examples: true}
+}
+
+//ParseComponents returns the components with only the given ones chosen.
+//none can be given to choose none of them.
+func ParseComponents(names []string) (*Components, error) {
+	c := &Components{}
+	for _, v := range names {
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "none":
+		case DatabaseComponent:
+			c.Database = true
+		case VaultComponent:
+			c.Vault = true
+		case RateLimiterComponent:
+			c.RateLimiter = true
+		case ExamplesComponent:
+			c.Examples = true
+		default:
+			return nil, fmt.Errorf("unknown component %s. Use any of %s or none", v, strings.Join(ComponentNames, ", "))
+		}
+	}
+	return c, nil
+}
+
+//Chosen returns a pointer to the choice of the component with the given name
+func (c *Components) Chosen(name string) *bool {
+	switch name {
+	case DatabaseComponent:
+		return &c.Database
+	case VaultComponent:
+		return &c.Vault
+	case RateLimiterComponent:
+		return &c.RateLimiter
+	case ExamplesComponent:
+		return &c.Examples
+	}
+	return nil
+}
+
+//Names returns the names of the chosen components
+func (c Components) Names() []string {
+	names := []string{}
+	for _, v := range ComponentNames {
+		if *c.Chosen(v) {
+			names = append(names, v)
+		}
+	}
+	return names
+}
@@ -0,0 +1,155 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project_test
+
+import (
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/project"
+)
+
+/*
+ * This file contains the tests written for the source code in components.go
+ */
+
+var parsecomponentstcs = []struct {
+	Name     string
+	Names    []string
+	Expected *project.Components
+	Error    bool
+}{
+	{"Single component", []string{"database"}, &project.Components{Database: true}, false},
+	{"Many components", []string{"vault", " RateLimiter", "examples"}, &project.Components{Vault: true, RateLimiter: true, Examples: true}, false},
+	{"No component", []string{"none"}, &project.Components{}, false},
+	{"Unknown component", []string{"cache"}, nil, true},
+}
+
+//TestParseComponents is the test suite for parsing the components given by name
+func TestParseComponents(t *testing.T) {
+	for _, v := range parsecomponentstcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			c, err := project.ParseComponents(v.Names)
+			if err == nil && v.Error {
+				t.Error("Expected an error. Got none.")
+				return
+			}
+			if err != nil && !v.Error {
+				t.Error("Didn't expect an error. Got one", err.Error())
+				return
+			}
+			if !reflect.DeepEqual(c, v.Expected) {
+				t.Error("Expected", v.Expected, "Got", c)
+			}
+		})
+	}
+}
+
+var setupcomponentstcs = []struct {
+	Name       string
+	Components *project.Components
+	Files      map[string]bool
+	Imports    map[string]bool
+}{
+	{
+		"All components by default",
+		nil,
+		map[string]bool{"config/vault.go": true, "config/database.go": true, "routes/ratelimiter.go": true, "routes/example_test.go": true},
+		map[string]bool{"github.com/jinzhu/gorm": true, "github.com/cuttle-ai/configs": true},
+	},
+	{
+		"No components",
+		&project.Components{},
+		map[string]bool{"config/vault.go": false, "config/database.go": false, "routes/ratelimiter.go": false, "routes/example_test.go": false},
+		map[string]bool{"github.com/jinzhu/gorm": false, "github.com/cuttle-ai/configs": false},
+	},
+	{
+		"Only the database",
+		&project.Components{Database: true},
+		map[string]bool{"config/vault.go": false, "config/database.go": true, "routes/ratelimiter.go": false, "routes/example_test.go": false},
+		map[string]bool{"github.com/jinzhu/gorm": true, "github.com/cuttle-ai/configs": false},
+	},
+	{
+		"Only vault and the rate limiter",
+		&project.Components{Vault: true, RateLimiter: true},
+		map[string]bool{"config/vault.go": true, "config/database.go": false, "routes/ratelimiter.go": true, "routes/example_test.go": false},
+		map[string]bool{"github.com/jinzhu/gorm": false, "github.com/cuttle-ai/configs": true},
+	},
+}
+
+//TestSetupComponents checks that only the files and imports of the chosen components are generated
+func TestSetupComponents(t *testing.T) {
+	project.Templates = os.DirFS(templatesDir)
+	for _, v := range setupcomponentstcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			dst := filepath.Join(t.TempDir(), "orders")
+			p := testProject(dst)
+			p.Components = v.Components
+			err := p.Setup()
+			if err != nil {
+				t.Fatal("Didn't expect an error. Got one", err)
+			}
+			for f, ok := range v.Files {
+				_, err := os.Stat(filepath.Join(dst, f))
+				if ok && err != nil {
+					t.Error("Expected", f, "to be generated. Got", err)
+				}
+				if !ok && !os.IsNotExist(err) {
+					t.Error("Expected", f, "not to be generated")
+				}
+			}
+
+			//checking the imports and the leftover directives of the generated files
+			imported := map[string]bool{}
+			err = filepath.WalkDir(dst, func(file string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() || filepath.Ext(file) != ".go" {
+					return err
+				}
+				b, err := ioutil.ReadFile(file)
+				if err != nil {
+					return err
+				}
+				if strings.Contains(string(b), "{{") {
+					t.Error("Expected the template directives to be rendered in", file)
+				}
+				for i := range v.Imports {
+					if strings.Contains(string(b), `"`+i) {
+						imported[i] = true
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, ok := range v.Imports {
+				if imported[i] != ok {
+					t.Error("Expected the import of", i, "to be", ok, "Got", imported[i])
+				}
+			}
+
+			//the chosen components are recorded in the metadata
+			m, err := project.ReadMetadata(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := v.Components
+			if expected == nil {
+				expected = project.AllComponents()
+			}
+			if !reflect.DeepEqual(m.Project.Components, expected) {
+				t.Error("Expected the components", expected, "in the metadata. Got", m.Project.Components)
+			}
+		})
+	}
+}
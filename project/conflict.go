@@ -0,0 +1,101 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"os"
+	"strings"
+)
+
+/*
+ * This file contains the definitions for handling the files already existing in the project destination
+ */
+
+//ConflictPolicy tells how the files already existing in the destination have to be handled
+type ConflictPolicy string
+
+const (
+	//Fail will fail the setup if any of the files to be generated already exists in the destination
+	Fail ConflictPolicy = "fail"
+	//Overwrite will overwrite the existing files in the destination
+	Overwrite ConflictPolicy = "overwrite"
+	//SkipExisting will keep the existing files in the destination as it is
+	SkipExisting ConflictPolicy = "skip"
+)
+
+//ConflictError is the error returned when files to be generated already exist in the destination
+type ConflictError struct {
+	//Files is the list of conflicting files relative to the destination
+	Files []string
+}
+
+//Error is the error implementation of the conflict error
+func (c ConflictError) Error() string {
+	return "the following files already exist in the destination. Use --force to overwrite or --skip-existing to keep them:\n  " +
+		strings.Join(c.Files, "\n  ")
+}
+
+//Conflicts returns the files relative to the destination that already exist and would be written by the setup
+//including the metadata of the project
+func (p Project) Conflicts() ([]string, error) {
+	/*
+	 * We will init the sources
+	 * Then check whether the destination file of each source and the metadata exists
+	 */
+	err := (&p).InitSources()
+	if err != nil {
+		return nil, err
+	}
+	conflicts := []string{}
+	for _, v := range append(p.sourcePaths(), MetadataFile) {
+		_, err := os.Stat(p.Destination + Separator + v)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, v)
+	}
+	return conflicts, nil
+}
+
+//sourcePaths returns the paths of the sources of the project relative to the destination.
+//The sources of the project have to be initialized before calling it.
+func (p Project) sourcePaths() []string {
+	paths := []string{}
+	for _, v := range p.Sources {
+		paths = append(paths, v.RelativePath())
+	}
+	return paths
+}
+
+//checkConflicts returns a ConflictError if any of the files to be generated, which are not asked to be kept,
+//already exist in the destination
+func (p Project) checkConflicts() error {
+	conflicts, err := p.Conflicts()
+	if err != nil {
+		return err
+	}
+	files := []string{}
+	for _, v := range conflicts {
+		if !p.Keep[v] {
+			files = append(files, v)
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	return ConflictError{Files: files}
+}
+
+//keep tells whether the existing file at the given path relative to the destination has to be kept as it is
+func (p Project) keep(file string) bool {
+	_, err := os.Stat(p.Destination + Separator + file)
+	if err != nil {
+		return false
+	}
+	return p.Keep[file] || p.OnConflict == SkipExisting
+}
@@ -0,0 +1,167 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project_test
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/project"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+/*
+ * This file contains the golden snapshot tests of the generated projects.
+ * The projects generated from fixed fixtures are compared against the golden files checked in testdata/golden.
+ * Run go test ./project -run TestGolden -update to refresh them after changing the boilerplate or the manifest
+ * and review the changes of the golden files along with the change.
+ */
+
+//update refreshes the golden files with the generated projects instead of comparing them
+var update = flag.Bool("update", false, "update the golden files of the generated projects in testdata/golden")
+
+//goldenDir is the directory having the golden files of the generated projects
+var goldenDir = filepath.Join("testdata", "golden")
+
+//goldenExt is the extension of the golden files. It keeps the golden files like .gitignore and go sources
+//from being picked up by git and the go tools.
+const goldenExt = ".golden"
+
+var goldentcs = []struct {
+	Name       string
+	Components *project.Components
+}{
+	{"all-components", nil},
+	{"no-components", &project.Components{}},
+}
+
+//TestGolden compares the projects generated from the fixtures with their golden files
+func TestGolden(t *testing.T) {
+	project.Templates = os.DirFS(templatesDir)
+	for _, v := range goldentcs {
+		t.Run(v.Name, func(t *testing.T) {
+			/*
+			 * We will generate the project of the fixture
+			 * If the golden files have to be updated, we will replace them with the generated files
+			 * Else we will compare the generated files with the golden files
+			 */
+			fmt.Println("Testing", v.Name)
+
+			//generating the project
+			dst := filepath.Join(t.TempDir(), "orders")
+			p := testProject(dst)
+			p.Components = v.Components
+			err := p.Setup()
+			if err != nil {
+				t.Fatal("Error while generating the project", err)
+			}
+			got, err := readTree(dst, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			//the metadata has the version of web-starter which changes with every release
+			delete(got, project.MetadataFile)
+
+			//updating the golden files
+			golden := filepath.Join(goldenDir, v.Name)
+			if *update {
+				writeGolden(t, golden, got)
+				return
+			}
+
+			//comparing with the golden files
+			want, err := readTree(golden, goldenExt)
+			if err != nil {
+				t.Fatal("Error while reading the golden files. Run the tests with -update to create them", err)
+			}
+			for _, f := range sortedKeys(want, got) {
+				w, inWant := want[f]
+				g, inGot := got[f]
+				switch {
+				case !inGot:
+					t.Error(f, "is in the golden files but not generated")
+				case !inWant:
+					t.Error(f, "is generated but not in the golden files")
+				case w != g:
+					diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+						A:        difflib.SplitLines(w),
+						B:        difflib.SplitLines(g),
+						FromFile: "golden/" + f,
+						ToFile:   "generated/" + f,
+						Context:  3,
+					})
+					t.Errorf("%s differs from the golden file\n%s", f, diff)
+				}
+			}
+			if t.Failed() {
+				t.Log("Run go test ./project -run TestGolden -update if the changes are intended")
+			}
+		})
+	}
+}
+
+//readTree reads the files in the given directory with their slash separated paths relative to it
+//after removing the given extension
+func readTree(dir, ext string) (map[string]string, error) {
+	files := map[string]string{}
+	err := filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		files[strings.TrimSuffix(filepath.ToSlash(rel), ext)] = string(b)
+		return nil
+	})
+	return files, err
+}
+
+//writeGolden replaces the golden files in the given directory with the given files
+func writeGolden(t *testing.T, dir string, files map[string]string) {
+	err := os.RemoveAll(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for f, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(f)+goldenExt)
+		err = os.MkdirAll(filepath.Dir(file), 0755)
+		if err == nil {
+			err = ioutil.WriteFile(file, []byte(content), 0644)
+		}
+		if err != nil {
+			t.Fatal("Error while writing the golden file", file, err)
+		}
+	}
+	t.Log("Updated the golden files in", dir)
+}
+
+//sortedKeys returns the keys of the given maps sorted
+func sortedKeys(maps ...map[string]string) []string {
+	keys := []string{}
+	seen := map[string]bool{}
+	for _, m := range maps {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,223 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project_test
+
+import (
+	"bytes"
+	"flag"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/project"
+)
+
+/*
+ * This file contains the test harness generating every variant of the template and building and testing it.
+ * The vault client which can't be fetched is directed to its stand in in testdata/modules. The rest of the dependencies,
+ * like gorm, are the real modules resolved offline from the module cache unless GOPROXY is set. The modules can be directed
+ * to other local directories, like a checkout of the real module, through the WEB_STARTER_REPLACE environment variable having
+ * module=directory pairs separated by commas. The variants are only generated and checked for leftover placeholders with -short.
+ * Dependencies which aren't available skip the build of the variant, except with -all-variants where they fail it.
+ * Every license type is built with all the components and every component combination is built with the MIT license.
+ * Use -all-variants to build every license type with every component combination, like in CI.
+ */
+
+//allVariants builds every license type with every component combination instead of each of them once
+var allVariants = flag.Bool("all-variants", false, "build every license type with every component combination of the template")
+
+//replaceEnv is the environment variable having the replace directives for the generated projects
+const replaceEnv = "WEB_STARTER_REPLACE"
+
+//modulesDir has the stand ins of the dependencies of the template by their module path
+var modulesDir = filepath.Join("testdata", "modules")
+
+//standIns are the modules of the dependencies of the template which can't be fetched and have a stand in in the modules directory.
+//The other dependencies are built against the real modules so that the variants fail on an api the module doesn't have.
+var standIns = []string{"github.com/cuttle-ai/configs"}
+
+//pinnedVersions are the versions of the real dependencies of the template the variants are built against.
+//They are required up front so that go mod tidy finds them in the module cache without looking up their latest version.
+var pinnedVersions = map[string]string{"github.com/jinzhu/gorm": "v1.9.16"}
+
+//leftoverPlaceholder matches a template placeholder which wasn't rendered
+var leftoverPlaceholder = regexp.MustCompile(`{{\s*-?\s*\.`)
+
+//missingPackage matches the packages which couldn't be found by go mod tidy
+var missingPackage = regexp.MustCompile(`cannot find module providing package (\S+?):?\s`)
+
+//componentCombinations returns every combination of the optional components
+func componentCombinations() []*project.Components {
+	combinations := []*project.Components{}
+	for i := 0; i < 1<<len(project.ComponentNames); i++ {
+		c := &project.Components{}
+		for j, v := range project.ComponentNames {
+			*c.Chosen(v) = i&(1<<j) != 0
+		}
+		combinations = append(combinations, c)
+	}
+	return combinations
+}
+
+//TestTemplateVariants generates the project for each license type and component combination
+//and checks that it has no leftover placeholders and that it vets, builds and passes its tests
+func TestTemplateVariants(t *testing.T) {
+	/*
+	 * We will get the offline environment for the go commands
+	 * Then for each variant we will generate the project
+	 * Then we will check for the leftover placeholders
+	 * Then we will vet, build and test it
+	 */
+	project.Templates = os.DirFS(templatesDir)
+	env, build := harnessEnv(t)
+	for _, l := range project.LicenseTypes {
+		for _, c := range componentCombinations() {
+			l, c := l, c
+			name := strings.Join(c.Names(), "+")
+			if len(name) == 0 {
+				name = "none"
+			}
+			t.Run(string(l)+"/"+name, func(t *testing.T) {
+				t.Parallel()
+				dst := filepath.Join(t.TempDir(), "orders")
+				p := testProject(dst)
+				p.License.Type, p.Components = l, c
+				err := p.Setup()
+				if err != nil {
+					t.Fatal("Error while generating the project", err)
+				}
+				checkPlaceholders(t, dst)
+				if build && (*allVariants || l == project.MIT || *c == *project.AllComponents()) {
+					buildVariant(t, p, env)
+				}
+			})
+		}
+	}
+}
+
+//harnessEnv returns the environment in which the go commands are run offline against the module cache.
+//If GOPROXY is set in the environment, it is used instead of the module cache.
+//It returns false if the generated projects can't be built.
+func harnessEnv(t *testing.T) ([]string, bool) {
+	if testing.Short() {
+		t.Log("Only generating the variants in the short mode")
+		return nil, false
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Log("Only generating the variants since go is not available")
+		return nil, false
+	}
+	env := append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if len(os.Getenv("GOPROXY")) > 0 {
+		return env, true
+	}
+	out, err := exec.Command("go", "env", "GOMODCACHE").Output()
+	if err != nil {
+		t.Log("Only generating the variants since the module cache couldn't be found", err)
+		return nil, false
+	}
+	cache := filepath.ToSlash(filepath.Join(strings.TrimSpace(string(out)), "cache", "download"))
+	if !strings.HasPrefix(cache, "/") {
+		cache = "/" + cache
+	}
+	return append(env, "GOPROXY=file://"+cache, "GOSUMDB=off"), true
+}
+
+//checkPlaceholders fails the test if any file of the generated project has a placeholder which wasn't rendered
+func checkPlaceholders(t *testing.T, dst string) {
+	err := filepath.WalkDir(dst, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if loc := leftoverPlaceholder.FindIndex(b); loc != nil {
+			line := bytes.Count(b[:loc[0]], []byte("\n")) + 1
+			t.Errorf("Leftover placeholder in %s:%d", file, line)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal("Error while checking the placeholders", err)
+	}
+}
+
+//buildVariant initializes the modules of the generated project with the replace directives and then vets, builds and tests it.
+//The test is skipped if any dependency of the project outside it isn't available offline unless all the variants are built.
+func buildVariant(t *testing.T, p project.Project, env []string) {
+	/*
+	 * We will initialize the modules with the pinned versions and the replace directives
+	 * We will tidy the modules, failing or skipping if the dependencies are not available
+	 * Then we will vet, build and test the project
+	 */
+	//initializing the modules
+	run(t, p.Destination, env, "go", "mod", "init", p.Package)
+	for _, m := range sortedKeys(pinnedVersions) {
+		run(t, p.Destination, env, "go", "mod", "edit", "-require", m+"@"+pinnedVersions[m])
+	}
+	replaces := map[string]string{}
+	for _, v := range standIns {
+		replaces[v] = filepath.Join(modulesDir, filepath.FromSlash(v))
+	}
+	for _, v := range strings.Split(os.Getenv(replaceEnv), ",") {
+		parts := strings.SplitN(strings.TrimSpace(v), "=", 2)
+		if len(parts) == 2 {
+			replaces[parts[0]] = parts[1]
+		}
+	}
+	for _, m := range sortedKeys(replaces) {
+		dir, err := filepath.Abs(replaces[m])
+		if err != nil {
+			t.Fatal(err)
+		}
+		run(t, p.Destination, env, "go", "mod", "edit", "-replace", m+"="+dir)
+	}
+
+	//tidying the modules
+	c := exec.Command("go", "mod", "tidy")
+	c.Dir, c.Env = p.Destination, env
+	out, err := c.CombinedOutput()
+	if err != nil {
+		for _, m := range missingPackage.FindAllStringSubmatch(string(out), -1) {
+			if strings.HasPrefix(m[1], p.Package) || strings.HasPrefix(m[1], "github.com/cuttle-ai/web-starter") {
+				t.Fatalf("The generated project imports the package %s which doesn't exist\n%s", m[1], out)
+			}
+		}
+		if missingPackage.Match(out) && *allVariants {
+			t.Fatalf("Dependencies of the project are not available offline. Set %s to build it\n%s", replaceEnv, out)
+		}
+		if missingPackage.Match(out) {
+			t.Skipf("Dependencies of the project are not available offline. Set %s to build it\n%s", replaceEnv, out)
+		}
+		t.Fatalf("go mod tidy failed\n%s", out)
+	}
+
+	//vetting, building and testing the project
+	checkVariant(t, p.Destination, env)
+}
+
+//checkVariant vets, builds and tests the generated project in the given directory having its modules initialized
+func checkVariant(t *testing.T, dir string, env []string) {
+	run(t, dir, env, "go", "vet", "./...")
+	run(t, dir, env, "go", "build", "./...")
+	run(t, dir, env, "go", "test", "./...")
+}
+
+//run runs the command in the given directory and fails the test with its output if it fails
+func run(t *testing.T, dir string, env []string, name string, args ...string) {
+	c := exec.Command(name, args...)
+	c.Dir, c.Env = dir, env
+	out, err := c.CombinedOutput()
+	if err != nil {
+		t.Fatalf("%s %s failed in %s: %v\n%s", name, strings.Join(args, " "), dir, err, out)
+	}
+}
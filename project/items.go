@@ -5,7 +5,9 @@
 package project
 
 import (
+	"fmt"
 	"go/build"
+	"io/fs"
 	"os"
 	"path/filepath"
 
@@ -13,7 +15,7 @@ import (
 )
 
 /*
- * This file contains the list of boilerplate code with required refactors
+ * This file contains the templates of the boilerplate code and the refactors required on them
  */
 
 //Separator based on the os
@@ -22,9 +24,6 @@ var Separator = string([]rune{filepath.Separator})
 //PackagePath is the package name of web starter project
 var PackagePath = "github.com" + Separator + "cuttle-ai" + Separator + "web-starter"
 
-//BoilerplatePackagePath is the package path of the boilr plate code
-var BoilerplatePackagePath = PackagePath + Separator + "boilerplate"
-
 //GoPath is the gopath in the system
 func GoPath() string {
 	/*
@@ -38,273 +37,60 @@ func GoPath() string {
 	return p + Separator + "src" + Separator
 }
 
-//LicensesPath is the location where all license templates are kept
-var LicensesPath = GoPath() + PackagePath + Separator + "licenses"
-
-//BoilerplatePath is the absolute path to the boilerplate code
-var BoilerplatePath = GoPath() + BoilerplatePackagePath
-
-//VersionPath is the path of the version package in the boilerplate code
-var VersionPath = BoilerplatePath + Separator + "version"
-
-//ConfigPath is the path of the config package in the boilerplate code
-var ConfigPath = BoilerplatePath + Separator + "config"
-
-//LogPath is the path of the log package in the boilerplate code
-var LogPath = BoilerplatePath + Separator + "log"
-
-//RoutesPath is the path of the routes package in the boilerplate code
-var RoutesPath = BoilerplatePath + Separator + "routes"
-
-//ResponsePath is the path of the response package in the boilerplate code
-var ResponsePath = RoutesPath + Separator + "response"
+//Templates is the file system having the boilerplate code and the license templates.
+//By default it is the web-starter source in the GOPATH. The web-starter binary replaces it
+//with the templates embedded in it, so that the generation doesn't depend on the GOPATH.
+var Templates fs.FS = os.DirFS(GoPath() + PackagePath)
 
-//LicenseRefactors returns the license refactors to be done in every go source file
-func (p *Project) LicenseRefactors() []generate.Refactor {
-	return []generate.Refactor{
-		{
-			Name:    LicenseOrganisation,
-			Find:    CuttleAI,
-			Replace: p.Author.String(),
-			Source:  generate.NewCommentRefactor(),
-		},
-		{
-			Name:    LicenseTemplate,
-			Find:    MitStyle,
-			Replace: p.Author.String(),
-			Source:  generate.NewCommentRefactor(),
-		},
-	}
-}
-
-//BoilerplatePackageRefactors returns the package name refactor to be done in every go source file
-func (p *Project) BoilerplatePackageRefactors() generate.Refactor {
+//LicenseRefactor returns the refactor writing the license header of the project in a go source file
+func (p *Project) LicenseRefactor() generate.Refactor {
 	return generate.Refactor{
-		Name:    BoilerPlatePackage,
-		Find:    BoilerplatePackagePath,
-		Replace: p.Package,
-		Source:  generate.NewPackageRefactor(),
-	}
-}
-
-func (p *Project) configSources() []generate.Source {
-	return []generate.Source{
-		{
-			Path:                ConfigPath,
-			FileName:            "config.go",
-			RelativeDestination: "config",
-			Refactors: []generate.Refactor{
-				p.BoilerplatePackageRefactors(),
-			},
-		},
-		{
-			Path:                ConfigPath,
-			FileName:            "context.go",
-			RelativeDestination: "config",
-			Refactors:           []generate.Refactor{},
-		},
-		{
-			Path:                ConfigPath,
-			FileName:            "logger.go",
-			RelativeDestination: "config",
-			Refactors:           []generate.Refactor{},
-		},
-	}
-}
-
-func (p *Project) logSources() []generate.Source {
-	return []generate.Source{
-		{
-			Path:                LogPath,
-			FileName:            "log.go",
-			RelativeDestination: "log",
-			Refactors: []generate.Refactor{
-				p.BoilerplatePackageRefactors(),
-			},
-		},
-		{
-			Path:                LogPath,
-			FileName:            "logger.go",
-			RelativeDestination: "log",
-			Refactors:           []generate.Refactor{},
-		},
+		Name:   LicenseHeader,
+		Source: generate.NewHeaderRefactor(p.License.Header()),
 	}
 }
 
-func (p *Project) routeSources() []generate.Source {
-	return []generate.Source{
-		{
-			Path:                RoutesPath,
-			FileName:            "routes.go",
-			RelativeDestination: "routes",
-			Refactors:           []generate.Refactor{},
-		},
-		{
-			Path:                RoutesPath,
-			FileName:            "route.go",
-			RelativeDestination: "routes",
-			Refactors: []generate.Refactor{
-				p.BoilerplatePackageRefactors(),
-			},
-		},
-		{
-			Path:                RoutesPath,
-			FileName:            "ratelimiter.go",
-			RelativeDestination: "routes",
-			Refactors: []generate.Refactor{
-				p.BoilerplatePackageRefactors(),
-			},
-		},
-		{
-			Path:                RoutesPath,
-			FileName:            "example_test.go",
-			RelativeDestination: "routes",
-			Refactors: []generate.Refactor{
-				p.BoilerplatePackageRefactors(),
-			},
-		},
-		{
-			Path:                ResponsePath,
-			FileName:            "response.go",
-			RelativeDestination: "routes" + Separator + "response",
-			Refactors: []generate.Refactor{
-				p.BoilerplatePackageRefactors(),
-			},
-		},
+//TemplateRefactors returns the refactor rendering the boilerplate code as a template against the project
+func (p *Project) TemplateRefactors() generate.Refactor {
+	return generate.Refactor{
+		Name:   TemplateRender,
+		Source: generate.NewTemplateRefactor(*p),
 	}
 }
 
-func (p *Project) mainSources() []generate.Source {
-	return []generate.Source{
-		{
-			Path:     BoilerplatePath,
-			FileName: "main.go",
-			Refactors: append([]generate.Refactor{
-				{
-					Name:    MainProjectName,
-					Find:    ProjectName,
-					Replace: p.Name,
-					Source:  generate.NewCommentRefactor(),
-				},
-				{
-					Name:    MainProjectDescription,
-					Find:    ProjectDescription,
-					Replace: p.Description,
-					Source:  generate.NewCommentRefactor(),
-				},
-				p.BoilerplatePackageRefactors(),
-			}, p.LicenseRefactors()...),
-		},
+//InitSources will init the sources of the project as per the manifest of its template
+func (p *Project) InitSources() error {
+	/*
+	 * We will resolve the template of the project
+	 * We will load the manifest of the template
+	 * Then we will get the sources from the manifest
+	 */
+	err := p.Template.Resolve()
+	if err != nil {
+		//error while resolving the template
+		fmt.Println("Error while resolving the template", p.Template, "for", p.Name)
+		return err
 	}
-}
-
-func (p *Project) gitSources() []generate.Source {
-	return []generate.Source{
-		{
-			Path:      BoilerplatePath,
-			FileName:  ".gitignore",
-			Refactors: []generate.Refactor{},
-		},
+	templates, err := p.templates()
+	if err != nil {
+		return err
 	}
-}
-
-func (p *Project) readmeSources() []generate.Source {
-	return []generate.Source{
-		{
-			Path:     BoilerplatePath,
-			FileName: "README.md",
-			Refactors: []generate.Refactor{
-				{
-					Name:    ReadmeProjectName,
-					Find:    ProjectName,
-					Replace: p.Name,
-					Source:  generate.NewNonGoFileRefactor(),
-				},
-				{
-					Name:    ReadmeProjectDescription,
-					Find:    ProjectDescription,
-					Replace: p.Description,
-					Source:  generate.NewNonGoFileRefactor(),
-				},
-				{
-					Name:    ReadmePackage,
-					Find:    Package,
-					Replace: p.Package,
-					Source:  generate.NewNonGoFileRefactor(),
-				},
-				{
-					Name:    ReadmeAuthorName,
-					Find:    AuthorName,
-					Replace: p.Author.Name,
-					Source:  generate.NewNonGoFileRefactor(),
-				},
-				{
-					Name:    ReadmeAuthorEmail,
-					Find:    AuthorEmail,
-					Replace: p.Author.Email,
-					Source:  generate.NewNonGoFileRefactor(),
-				},
-			},
-		},
+	m, err := LoadManifest(templates)
+	if err != nil {
+		//error while loading the manifest
+		fmt.Println("Error while loading the manifest of the templates for", p.Name)
+		return err
 	}
-}
-
-func (p *Project) licenseSources() []generate.Source {
-	return []generate.Source{
-		{
-			Path:     LicensesPath + Separator + string(p.License.Type),
-			FileName: "LICENSE",
-			Refactors: []generate.Refactor{
-				{
-					Name:    LicenseProjectName,
-					Find:    ProjectName,
-					Replace: p.Name,
-					Source:  generate.NewNonGoFileRefactor(),
-				},
-				{
-					Name:    LicenseOrganisation,
-					Find:    Organisation,
-					Replace: p.License.Organisation,
-					Source:  generate.NewNonGoFileRefactor(),
-				},
-				{
-					Name:    LicenseYear,
-					Find:    Year,
-					Replace: p.License.Year,
-					Source:  generate.NewNonGoFileRefactor(),
-				},
-			},
-		},
+	p.Sources, err = m.Sources(p)
+	if err != nil {
+		//error while getting the sources from the manifest
+		fmt.Println("Error while getting the sources from the manifest for", p.Name)
+		return err
 	}
-}
 
-func (p *Project) versionSources() []generate.Source {
-	return []generate.Source{
-		{
-			Path:                VersionPath,
-			FileName:            "version.go",
-			RelativeDestination: "version",
-			Refactors: []generate.Refactor{
-				{
-					Name:    VersionProjectName,
-					Find:    ProjectName,
-					Replace: p.Name,
-					Source:  generate.NewNonGoFileRefactor(),
-				},
-			},
-		},
+	//all the sources are read from the template
+	for i := range p.Sources {
+		p.Sources[i].FS = templates
 	}
-}
-
-//InitSources will init the sources of the project
-func (p *Project) InitSources() {
-	p.Sources = []generate.Source{}
-	p.Sources = append(p.Sources, p.mainSources()...)
-	p.Sources = append(p.Sources, p.gitSources()...)
-	p.Sources = append(p.Sources, p.readmeSources()...)
-	p.Sources = append(p.Sources, p.licenseSources()...)
-	p.Sources = append(p.Sources, p.versionSources()...)
-	p.Sources = append(p.Sources, p.configSources()...)
-	p.Sources = append(p.Sources, p.logSources()...)
-	p.Sources = append(p.Sources, p.routeSources()...)
+	return nil
 }
@@ -0,0 +1,147 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+/*
+ * This file contains the utilities for discovering the license templates. Apart from the license templates
+ * in the template of the project, the user can give a directory having company internal license templates.
+ */
+
+//LicensesPath is the directory in the templates having a directory with the LICENSE file for each license type
+const LicensesPath = "licenses"
+
+//spdx has the SPDX identifiers of the open source license types
+var spdx = map[LicenseType]string{
+	AGPL3:      "AGPL-3.0-only",
+	APACHE2:    "Apache-2.0",
+	BSD2:       "BSD-2-Clause",
+	BSD3:       "BSD-3-Clause",
+	GPL2:       "GPL-2.0-only",
+	GPL3:       "GPL-3.0-only",
+	LGPL3:      "LGPL-3.0-only",
+	MIT:        "MIT",
+	MPL2:       "MPL-2.0",
+	UNLICENSED: "Unlicense",
+}
+
+//licenseNames are the names of the open source license types used in the license headers
+var licenseNames = map[LicenseType]string{
+	AGPL3:   "GNU Affero General Public License v3.0",
+	APACHE2: "Apache License 2.0",
+	BSD2:    "BSD 2-Clause license",
+	BSD3:    "BSD 3-Clause license",
+	GPL2:    "GNU General Public License v2.0",
+	GPL3:    "GNU General Public License v3.0",
+	LGPL3:   "GNU Lesser General Public License v3.0",
+	MIT:     "MIT license",
+	MPL2:    "Mozilla Public License 2.0",
+}
+
+//notSPDXChar matches the characters not allowed in the SPDX license references
+var notSPDXChar = regexp.MustCompile(`[^A-Za-z0-9.-]`)
+
+//SPDX returns the SPDX identifier of the license. The license types which are not open source
+//get a license reference like LicenseRef-CLOSED.
+func (l License) SPDX() string {
+	if id, ok := spdx[l.Type]; ok {
+		return id
+	}
+	return "LicenseRef-" + notSPDXChar.ReplaceAllString(string(l.Type), "-")
+}
+
+//Header returns the license header to be written at the top of the go source files without the comment markers.
+//It has the copyright of the organisation, the license governing the source code and its SPDX identifier.
+func (l License) Header() string {
+	if l.Type == UNLICENSED {
+		return "This is free and unencumbered software released into the public domain.\n" +
+			"For more information, see the LICENSE file.\n\n" +
+			"SPDX-License-Identifier: " + l.SPDX()
+	}
+	name, ok := licenseNames[l.Type]
+	if !ok {
+		name = "license"
+	}
+	return "Copyright " + l.Year + " " + strings.TrimSuffix(l.Organisation, ".") + ". All rights reserved.\n" +
+		"Use of this source code is governed by the " + name + "\n" +
+		"that can be found in the LICENSE file.\n\n" +
+		"SPDX-License-Identifier: " + l.SPDX()
+}
+
+//Licenses returns the license types whose license templates are present in the template of the project
+//or in the licenses directory of the project. The template has to be resolved before.
+func (p Project) Licenses() ([]LicenseType, error) {
+	/*
+	 * We will find the license templates in the template
+	 * Then we will find the ones in the licenses directory
+	 */
+	templates, err := p.Template.FS()
+	if err != nil {
+		return nil, err
+	}
+	found := map[LicenseType]bool{}
+	files, err := fs.Glob(templates, LicensesPath+"/*/LICENSE")
+	if err != nil {
+		return nil, err
+	}
+	if len(p.LicensesDir) > 0 {
+		extra, err := fs.Glob(os.DirFS(p.LicensesDir), "*/LICENSE")
+		if err != nil {
+			return nil, err
+		}
+		if len(extra) == 0 {
+			return nil, fmt.Errorf("couldn't find any license templates like <type>/LICENSE in the licenses directory %s", p.LicensesDir)
+		}
+		files = append(files, extra...)
+	}
+	types := []LicenseType{}
+	for _, v := range files {
+		t := LicenseType(path.Base(path.Dir(v)))
+		if !found[t] {
+			found[t] = true
+			types = append(types, t)
+		}
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types, nil
+}
+
+//templates returns the file system of the template of the project with the license templates
+//in the licenses directory of the project overlaid on it
+func (p Project) templates() (fs.FS, error) {
+	templates, err := p.Template.FS()
+	if err != nil || len(p.LicensesDir) == 0 {
+		return templates, err
+	}
+	return licensesFS{FS: templates, licenses: os.DirFS(p.LicensesDir)}, nil
+}
+
+//licensesFS overlays the license templates in a directory on the licenses directory of the templates
+type licensesFS struct {
+	fs.FS
+	//licenses is the file system of the directory having the license templates
+	licenses fs.FS
+}
+
+//Open opens the named file from the license templates if it is under the licenses directory and
+//is present in them. Else it is opened from the templates.
+func (l licensesFS) Open(name string) (fs.File, error) {
+	if rel := strings.TrimPrefix(name, LicensesPath+"/"); rel != name {
+		f, err := l.licenses.Open(rel)
+		if err == nil {
+			return f, nil
+		}
+	}
+	return l.FS.Open(name)
+}
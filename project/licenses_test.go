@@ -0,0 +1,131 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project_test
+
+import (
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/project"
+)
+
+/*
+ * This file contains the tests written for the source code in licenses.go
+ */
+
+var spdxtcs = []struct {
+	Name     string
+	Type     project.LicenseType
+	Expected string
+}{
+	{"Open source license", project.APACHE2, "Apache-2.0"},
+	{"Public domain", project.UNLICENSED, "Unlicense"},
+	{"Closed source", project.CLOSED, "LicenseRef-CLOSED"},
+	{"Company internal license", "Acme Internal", "LicenseRef-Acme-Internal"},
+}
+
+//TestSPDX is the test suite for the SPDX identifiers of the licenses
+func TestSPDX(t *testing.T) {
+	for _, v := range spdxtcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			if got := (project.License{Type: v.Type}).SPDX(); got != v.Expected {
+				t.Error("Expected", v.Expected, "Got", got)
+			}
+		})
+	}
+}
+
+//TestLicenses checks the license types are discovered from the templates and the licenses directory
+func TestLicenses(t *testing.T) {
+	/*
+	 * We will check the built in licenses are discovered
+	 * Then we will add a license in a licenses directory
+	 * Then we will generate a project with it
+	 */
+	project.Templates = os.DirFS(templatesDir)
+	p := testProject(filepath.Join(t.TempDir(), "orders"))
+	licenses, err := p.Licenses()
+	if err != nil {
+		t.Fatal("Error while getting the licenses", err)
+	}
+	if !reflect.DeepEqual(licenses, project.LicenseTypes) {
+		t.Error("Expected the built in licenses to be", project.LicenseTypes, "Got", licenses)
+	}
+
+	//adding a license in the licenses directory
+	p.LicensesDir = t.TempDir()
+	err = os.Mkdir(filepath.Join(p.LicensesDir, "ACME"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(filepath.Join(p.LicensesDir, "ACME", "LICENSE"), "Internal to {{.License.Organisation}}\n")
+	licenses, err = p.Licenses()
+	if err != nil {
+		t.Fatal("Error while getting the licenses", err)
+	}
+	if len(licenses) != len(project.LicenseTypes)+1 || licenses[0] != "ACME" {
+		t.Error("Expected ACME to be added to the licenses. Got", licenses)
+	}
+
+	//generating the project with the license
+	p.License.Type = "ACME"
+	err = p.Setup()
+	if err != nil {
+		t.Fatal("Error while generating the project with the license in the licenses directory", err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(p.Destination, "LICENSE"))
+	if err != nil || string(b) != "Internal to Example Inc\n" {
+		t.Error("Expected the license to be rendered from the licenses directory. Got", string(b), err)
+	}
+	m, err := project.ReadMetadata(p.Destination)
+	if err != nil || m.SPDX != "LicenseRef-ACME" || !strings.HasPrefix(m.Project.LicensesDir, p.LicensesDir) {
+		t.Error("Expected the metadata to record the SPDX identifier and the licenses directory. Got", m, err)
+	}
+}
+
+//TestLicenseHeaders generates a project for every license type and checks the license header of every go file
+func TestLicenseHeaders(t *testing.T) {
+	project.Templates = os.DirFS(templatesDir)
+	for _, l := range project.LicenseTypes {
+		t.Run(string(l), func(t *testing.T) {
+			fmt.Println("Testing the license header of", l)
+			p := testProject(filepath.Join(t.TempDir(), "orders"))
+			p.License.Type = l
+			err := p.Setup()
+			if err != nil {
+				t.Fatal("Error while generating the project", err)
+			}
+			first := "// " + strings.Split(p.License.Header(), "\n")[0] + "\n"
+			spdx := "// SPDX-License-Identifier: " + p.License.SPDX() + "\n"
+			err = filepath.WalkDir(p.Destination, func(file string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() || filepath.Ext(file) != ".go" {
+					return err
+				}
+				b, err := ioutil.ReadFile(file)
+				if err != nil {
+					return err
+				}
+				src := string(b)
+				if !strings.HasPrefix(src, first) || !strings.Contains(src, spdx) {
+					t.Errorf("Expected %s to start with the %s license header. Got\n%s", file, l, src[:200])
+				}
+				if strings.Contains(src, "Cuttle.ai") || strings.Contains(src, "MIT-style") {
+					t.Errorf("Expected the license header of the template to be replaced in %s", file)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
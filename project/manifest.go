@@ -0,0 +1,349 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/cuttle-ai/web-starter/generate"
+	"gopkg.in/yaml.v3"
+)
+
+/*
+ * This file contains the definitions of the template manifest. The manifest lists the files of a template
+ * with their destinations, refactors and conditions.
+ */
+
+//ManifestFiles are the names of the manifest file looked up at the root of the templates in the order of preference
+var ManifestFiles = []string{"manifest.yaml", "manifest.yml", "manifest.json"}
+
+const (
+	//PackageRefactorKind replaces the package of the template in the imports with the project package
+	PackageRefactorKind = "package"
+	//CommentRefactorKind replaces the find string with the replace string in the comments
+	CommentRefactorKind = "comment"
+	//TextRefactorKind replaces the find string with the replace string in the whole file
+	TextRefactorKind = "text"
+	//StringRefactorKind replaces the find string with the replace string in the string literals of a go file
+	StringRefactorKind = "string"
+	//IdentifierRefactorKind replaces the find string with the replace string in the identifiers of a go file
+	IdentifierRefactorKind = "identifier"
+)
+
+//Manifest lists the files of a template
+type Manifest struct {
+	//Package is the import path of the template code which is replaced by the project package
+	Package string `json:"package" yaml:"package"`
+	//Files are the files in the template
+	Files []ManifestFile `json:"files" yaml:"files"`
+}
+
+//ManifestFile is a file in the template
+type ManifestFile struct {
+	//Path is the slash separated path of the file in the template. It is rendered against the project.
+	//It can also be a glob pattern or a directory to take all the files matched by it
+	Path string `json:"path" yaml:"path"`
+	//Destination is the directory in the generated project to put the file in. Empty means the project root
+	Destination string `json:"destination" yaml:"destination"`
+	//When is the condition rendered against the project. The file is generated only if it renders to true
+	When string `json:"when" yaml:"when"`
+	//Name is the name of the file in the generated project. It is rendered against the project.
+	//Defaults to the name of the file in the template. It can be given only if the path is a single file
+	Name string `json:"name" yaml:"name"`
+	//Mode is the octal permission of the generated files like 0755. Defaults to 0755 for the executable files in
+	//the template and 0644 for the rest
+	Mode string `json:"mode" yaml:"mode"`
+	//Raw copies the files as they are without rendering or refactoring them. The binary files are always copied as they are
+	Raw bool `json:"raw" yaml:"raw"`
+	//Refactors are the refactors to be done on the file after rendering it
+	Refactors []ManifestRefactor `json:"refactors" yaml:"refactors"`
+}
+
+//ManifestRefactor is a refactor to be done on a file in the template
+type ManifestRefactor struct {
+	//Kind is the kind of refactor
+	Kind string `json:"kind" yaml:"kind"`
+	//Name of the refactor. Defaults to a name made from the kind
+	Name string `json:"name" yaml:"name"`
+	//Find is the string to be found
+	Find string `json:"find" yaml:"find"`
+	//Replace is the string to be replaced in the place of the string found. It is rendered against the project
+	Replace string `json:"replace" yaml:"replace"`
+	//Regex indicates that the string to be found is a regular expression. The replace string can refer to its capture groups like $1
+	Regex bool `json:"regex" yaml:"regex"`
+	//Expect fails the generation if the string to be found didn't match anything in the file
+	Expect bool `json:"expect" yaml:"expect"`
+}
+
+//LoadManifest reads the manifest at the root of the given templates
+func LoadManifest(templates fs.FS) (*Manifest, error) {
+	/*
+	 * We will look for the manifest files in the order of preference
+	 * Then decode the first one found
+	 */
+	for _, v := range ManifestFiles {
+		b, err := fs.ReadFile(templates, v)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			//error while reading the manifest
+			fmt.Println("Error while reading the manifest", v)
+			return nil, err
+		}
+		m := &Manifest{}
+		if path.Ext(v) == ".json" {
+			err = json.Unmarshal(b, m)
+		} else {
+			err = yaml.Unmarshal(b, m)
+		}
+		if err != nil {
+			//error while decoding the manifest
+			fmt.Println("Error while decoding the manifest", v)
+			return nil, err
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("couldn't find any of the manifest files %s in the templates", strings.Join(ManifestFiles, ", "))
+}
+
+//Sources returns the sources of the given project as per the manifest.
+//All the optional components are chosen for the project if it didn't choose them.
+func (m Manifest) Sources(p *Project) ([]generate.Source, error) {
+	/*
+	 * We will choose all the components of the project if they are not chosen
+	 * We will iterate through the files in the manifest
+	 * We will skip the files whose condition isn't met
+	 * We will find the template files matched by the path of the file
+	 * Then we will make the source of each of them
+	 */
+	if p.Components == nil {
+		p.Components = AllComponents()
+	}
+	templates, err := p.templates()
+	if err != nil {
+		return nil, err
+	}
+	sources := []generate.Source{}
+	for _, v := range m.Files {
+		//checking the condition
+		if len(v.When) > 0 {
+			ok, err := p.execute(v.When)
+			if err != nil {
+				//error while checking the condition of the file
+				fmt.Println("Error while checking the condition of the file", v.Path, "in the manifest")
+				return nil, err
+			}
+			if strings.TrimSpace(ok) != "true" {
+				continue
+			}
+		}
+
+		//finding the template files
+		file, err := p.execute(v.Path)
+		if err != nil {
+			//error while rendering the path of the file
+			fmt.Println("Error while rendering the path of the file", v.Path, "in the manifest")
+			return nil, err
+		}
+		matches, err := expand(templates, file, v.Destination)
+		if err != nil {
+			//error while finding the files of the path
+			fmt.Println("Error while finding the template files of", v.Path, "in the manifest")
+			return nil, err
+		}
+		if len(v.Name) > 0 && (len(matches) != 1 || matches[0].file != file) {
+			return nil, fmt.Errorf("name %s of %s in the manifest can only be given for a single file", v.Name, v.Path)
+		}
+
+		//making the sources
+		for _, f := range matches {
+			s, err := m.source(p, v, f)
+			if err != nil {
+				//error while making the source of the file
+				fmt.Println("Error while making the source of the file", f.file, "in the manifest")
+				return nil, err
+			}
+			sources = append(sources, *s)
+		}
+	}
+	return sources, nil
+}
+
+//source returns the source of the project for the given template file matched by the file in the manifest
+func (m Manifest) source(p *Project, v ManifestFile, f match) (*generate.Source, error) {
+	/*
+	 * We will make the source with its name and mode
+	 * Then we will add the refactors of the file
+	 * The go source files get the license header of the project
+	 * Then we will compile the refactors of the source
+	 */
+	s := &generate.Source{
+		Path:                path.Dir(f.file),
+		FileName:            path.Base(f.file),
+		RelativeDestination: filepath.FromSlash(f.destination),
+		Raw:                 v.Raw,
+	}
+	if len(v.Name) > 0 {
+		name, err := p.execute(v.Name)
+		if err != nil {
+			return nil, err
+		}
+		s.DestinationName = name
+	}
+	if len(v.Mode) > 0 {
+		mode, err := strconv.ParseUint(v.Mode, 8, 32)
+		if err != nil || mode > 0777 {
+			return nil, fmt.Errorf("mode %s of %s in the manifest is not an octal permission like 0755", v.Mode, v.Path)
+		}
+		s.Mode = fs.FileMode(mode)
+	}
+	if v.Raw {
+		return s, nil
+	}
+
+	//adding the refactors
+	s.Refactors = []generate.Refactor{p.TemplateRefactors()}
+	for _, r := range v.Refactors {
+		refs, err := m.refactors(p, r)
+		if err != nil {
+			//error while making the refactor of the file
+			fmt.Println("Error while making the refactors of the file", v.Path, "in the manifest")
+			return nil, err
+		}
+		s.Refactors = append(s.Refactors, refs...)
+	}
+	if path.Ext(s.FileName) == ".go" {
+		s.Refactors = append(s.Refactors, p.LicenseRefactor())
+	}
+	err := s.Compile()
+	if err != nil {
+		//error while compiling the refactors of the file
+		fmt.Println("Error while compiling the refactors of the file", v.Path, "in the manifest")
+		return nil, err
+	}
+	return s, nil
+}
+
+//match is a template file matched by the path of a file in the manifest
+type match struct {
+	//file is the slash separated path of the file in the templates
+	file string
+	//destination is the slash separated directory in the generated project to put the file in
+	destination string
+}
+
+//expand returns the template files matched by the given path of a file in the manifest along with their destinations.
+//The path can be a file, a glob pattern or a directory. The files in a directory are taken with their sub directories
+//kept under the destination.
+func expand(templates fs.FS, file, destination string) ([]match, error) {
+	/*
+	 * If the path is a glob pattern, we will expand each of its matches
+	 * If the path is a directory, we will walk through it
+	 * Else the path is the file
+	 */
+	if strings.ContainsAny(file, "*?[") {
+		files, err := fs.Glob(templates, file)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("the pattern %s didn't match any file in the templates", file)
+		}
+		matches := []match{}
+		for _, v := range files {
+			m, err := expand(templates, v, destination)
+			if err != nil {
+				return nil, err
+			}
+			matches = append(matches, m...)
+		}
+		return matches, nil
+	}
+	if info, err := fs.Stat(templates, file); err != nil || !info.IsDir() {
+		//the missing files are reported while generating them
+		return []match{{file: file, destination: destination}}, nil
+	}
+	matches := []match{}
+	err := fs.WalkDir(templates, file, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(path.Dir(p), file), "/")
+		matches = append(matches, match{file: p, destination: path.Join(destination, rel)})
+		return nil
+	})
+	return matches, err
+}
+
+//refactors returns the refactors of the project for the given refactor in the manifest
+func (m Manifest) refactors(p *Project, r ManifestRefactor) ([]generate.Refactor, error) {
+	/*
+	 * The package refactor is made from the project
+	 * The rest has the replace string rendered against the project
+	 */
+	if r.Kind == PackageRefactorKind {
+		return []generate.Refactor{
+			{
+				Name:    BoilerPlatePackage,
+				Find:    m.Package,
+				Replace: p.Package,
+				Source:  generate.NewPackageRefactor(),
+			},
+		}, nil
+	}
+
+	var source generate.RefactorSource
+	switch r.Kind {
+	case CommentRefactorKind:
+		source = generate.NewCommentRefactor()
+	case TextRefactorKind:
+		source = generate.NewNonGoFileRefactor()
+	case StringRefactorKind:
+		source = generate.NewStringLiteralRefactor()
+	case IdentifierRefactorKind:
+		source = generate.NewIdentifierRefactor()
+	default:
+		return nil, fmt.Errorf("unknown refactor kind %s", r.Kind)
+	}
+	replace, err := p.execute(r.Replace)
+	if err != nil {
+		return nil, err
+	}
+	name := r.Name
+	if len(name) == 0 {
+		name = r.Kind + " " + r.Find
+	}
+	return []generate.Refactor{
+		{
+			Name:          name,
+			Find:          r.Find,
+			Replace:       replace,
+			IsRegex:       r.Regex,
+			ExpectMatches: r.Expect,
+			Source:        source,
+		},
+	}, nil
+}
+
+//execute renders the given text as a template against the project
+func (p *Project) execute(text string) (string, error) {
+	t, err := template.New("manifest").Option("missingkey=error").Funcs(generate.TemplateFuncs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	b := &bytes.Buffer{}
+	err = t.Execute(b, *p)
+	return b.String(), err
+}
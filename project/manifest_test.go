@@ -0,0 +1,176 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project_test
+
+import (
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/project"
+)
+
+/*
+ * This file contains the tests written for the source code in manifest.go
+ */
+
+//templateAction matches the template actions in a path of the manifest
+var templateAction = regexp.MustCompile(`{{[^}]*}}`)
+
+//TestManifestCoversBoilerplate fails if any file in the boilerplate is not listed in the manifest
+func TestManifestCoversBoilerplate(t *testing.T) {
+	/*
+	 * We will load the manifest
+	 * Then we will turn the paths in it to glob patterns
+	 * Then we will walk the boilerplate and check each file is matched by a pattern or is in a directory of the manifest
+	 */
+	templates := os.DirFS(templatesDir)
+	m, err := project.LoadManifest(templates)
+	if err != nil {
+		t.Fatal("Error while loading the manifest", err)
+	}
+	patterns := []string{}
+	for _, v := range m.Files {
+		patterns = append(patterns, templateAction.ReplaceAllString(v.Path, "*"))
+	}
+	err = fs.WalkDir(templates, "boilerplate", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		for _, v := range patterns {
+			if ok, _ := path.Match(v, p); ok || strings.HasPrefix(p, v+"/") {
+				return nil
+			}
+		}
+		t.Error(p, "is not listed in the manifest. It won't be generated in the projects")
+		return nil
+	})
+	if err != nil {
+		t.Fatal("Error while walking the boilerplate", err)
+	}
+}
+
+//TestManifestSources checks that the sources of the manifest exist in the templates for every license type
+func TestManifestSources(t *testing.T) {
+	templates := os.DirFS(templatesDir)
+	m, err := project.LoadManifest(templates)
+	if err != nil {
+		t.Fatal("Error while loading the manifest", err)
+	}
+	for _, l := range project.LicenseTypes {
+		p := testProject(t.TempDir())
+		p.License.Type = l
+		sources, err := m.Sources(&p)
+		if err != nil {
+			t.Error("Error while getting the sources for the license", l, err)
+			continue
+		}
+		for _, v := range sources {
+			if _, err := fs.Stat(templates, v.Name()); err != nil {
+				t.Error("Source", v.Name(), "of the manifest doesn't exist in the templates for the license", l)
+			}
+		}
+	}
+}
+
+//TestManifestInvalidRegex checks that an invalid regex refactor is reported while making the sources
+func TestManifestInvalidRegex(t *testing.T) {
+	m := project.Manifest{
+		Files: []project.ManifestFile{
+			{
+				Path: "README.md",
+				Refactors: []project.ManifestRefactor{
+					{Kind: project.TextRefactorKind, Find: "(web", Replace: "$1", Regex: true},
+				},
+			},
+		},
+	}
+	p := testProject(t.TempDir())
+	_, err := m.Sources(&p)
+	if err == nil || !strings.Contains(err.Error(), "invalid regular expression") {
+		t.Error("Expected an invalid regular expression error. Got", err)
+	}
+}
+
+//TestManifestAssets checks that the directories, glob patterns, renames, modes, raw and binary files of the manifest are generated
+func TestManifestAssets(t *testing.T) {
+	/*
+	 * We will make a template having scripts, assets and files to be renamed
+	 * Then we will set up a project from it
+	 * Then we will check the generated files and their permissions
+	 */
+	//making the template
+	tmpl := t.TempDir()
+	files := map[string]string{
+		"manifest.yaml": "files:\n" +
+			"  - path: scripts\n    destination: scripts\n" +
+			"  - path: static/*.png\n    destination: static\n" +
+			"  - path: _gitignore\n    name: .gitignore\n" +
+			"  - path: Makefile\n    mode: \"0700\"\n" +
+			"  - path: page.tmpl\n    raw: true\n",
+		"scripts/run.sh":       "#!/bin/sh\necho {{.Name}}\n",
+		"scripts/sql/init.sql": "CREATE TABLE {{snake .Name}} (id INT);\n",
+		"static/favicon.png":   "\x89PNG\x00{{.Missing}}",
+		"_gitignore":           "*.exe\n",
+		"Makefile":             "build:\n\tgo build\n",
+		"page.tmpl":            "<h1>{{.Title}}</h1>\n",
+	}
+	for k, v := range files {
+		err := os.MkdirAll(filepath.Dir(filepath.Join(tmpl, k)), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeFile(filepath.Join(tmpl, k), v)
+	}
+	err := os.Chmod(filepath.Join(tmpl, "scripts", "run.sh"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//setting up the project
+	dst := filepath.Join(t.TempDir(), "orders")
+	p := testProject(dst)
+	p.Template = project.Template{Source: tmpl}
+	err = p.Setup()
+	if err != nil {
+		t.Fatal("Error while setting up the project", err)
+	}
+
+	//checking the generated files
+	expected := []struct {
+		Path    string
+		Content string
+		Mode    os.FileMode
+	}{
+		{"scripts/run.sh", "#!/bin/sh\necho Orders\n", 0755},
+		{"scripts/sql/init.sql", "CREATE TABLE orders (id INT);\n", 0644},
+		{"static/favicon.png", "\x89PNG\x00{{.Missing}}", 0644},
+		{".gitignore", "*.exe\n", 0644},
+		{"Makefile", "build:\n\tgo build\n", 0700},
+		{"page.tmpl", "<h1>{{.Title}}</h1>\n", 0644},
+	}
+	for _, v := range expected {
+		fmt.Println("Testing", v.Path)
+		file := filepath.Join(dst, filepath.FromSlash(v.Path))
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Error("Expected", v.Path, "to be generated.", err)
+			continue
+		}
+		if string(b) != v.Content {
+			t.Errorf("Expected %s to have\n%q\nGot\n%q", v.Path, v.Content, string(b))
+		}
+		info, err := os.Stat(file)
+		if err == nil && info.Mode().Perm() != v.Mode {
+			t.Errorf("Expected %s to have the permission %v. Got %v", v.Path, v.Mode, info.Mode().Perm())
+		}
+	}
+}
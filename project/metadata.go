@@ -0,0 +1,76 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/cuttle-ai/web-starter/version"
+)
+
+/*
+ * This file contains the definitions of the metadata recorded in the generated project
+ */
+
+//MetadataFile is the file in the generated project recording how the project was generated
+const MetadataFile = ".web-starter.json"
+
+//Metadata is the information recorded in the generated project about how it was generated
+type Metadata struct {
+	//Version of web-starter which generated the project
+	Version string `json:"version"`
+	//SPDX is the SPDX identifier of the license of the project
+	SPDX string `json:"spdx"`
+	//Project has the answers with which the project was generated including its template
+	Project Project `json:"project"`
+}
+
+//Metadata returns the metadata of the project to be recorded in the generated project.
+//The destination is left out since the project can be moved around after generation.
+func (p Project) Metadata() Metadata {
+	p.Destination = ""
+	return Metadata{Version: version.Default.Version, SPDX: p.License.SPDX(), Project: p}
+}
+
+//ReadMetadata reads the metadata recorded in the project generated in the given directory
+func ReadMetadata(dir string) (*Metadata, error) {
+	b, err := ioutil.ReadFile(dir + Separator + MetadataFile)
+	if err != nil {
+		//error while reading the metadata
+		fmt.Println("Error while reading the metadata of the project in", dir, "Was it generated by web-starter?")
+		return nil, err
+	}
+	m := &Metadata{}
+	err = json.Unmarshal(b, m)
+	if err != nil {
+		//error while decoding the metadata
+		fmt.Println("Error while decoding the metadata", MetadataFile, "of the project in", dir)
+		return nil, err
+	}
+	m.Project.Destination = dir
+	return m, nil
+}
+
+//writeMetadata writes the metadata of the project into the given directory
+func (p Project) writeMetadata(dir string) error {
+	return p.Metadata().write(dir)
+}
+
+//write writes the metadata into the project in the given directory
+func (m Metadata) write(dir string) error {
+	m.Project.Destination = ""
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(dir+Separator+MetadataFile, append(b, '\n'), 0644)
+	if err != nil {
+		//error while writing the metadata
+		fmt.Println("Error while writing the metadata of the project", m.Project.Name)
+	}
+	return err
+}
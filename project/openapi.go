@@ -0,0 +1,300 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"path/filepath"
+	"strings"
+
+	"github.com/cuttle-ai/web-starter/openapi"
+)
+
+/*
+ * This file contains the generation of the routes of a project from its OpenAPI document
+ */
+
+//openAPIGenerated is the name of the files in the api and routes packages having the code generated from the OpenAPI document
+const openAPIGenerated = "openapi_gen"
+
+//stubHeader is the part of the header of the stub files added by the sync by which they are told apart from the other routes
+const stubHeader = "It is added once by web-starter openapi sync"
+
+//OpenAPISync is the result of generating the code of the OpenAPI document in a project
+type OpenAPISync struct {
+	//Generated are the files generated again from the document relative to the project
+	Generated []string
+	//Added are the files having the stubs of the new operations relative to the project
+	Added []string
+	//Kept are the stubs of the operations already in the project which were left as they are
+	Kept []string
+	//Outdated are the stubs whose signature doesn't match their operation in the document anymore along with the expected signature
+	Outdated []string
+	//Orphaned are the files having the stubs of the operations removed from or renamed in the document relative to the project.
+	//They refer to the types which aren't generated anymore, so they have to be deleted or moved to the new operations.
+	Orphaned []string
+}
+
+//String returns the files generated and the stubs added, kept and to be updated one per line
+func (o OpenAPISync) String() string {
+	b := &strings.Builder{}
+	for _, v := range o.Generated {
+		fmt.Fprintf(b, "%-8s %s\n", "updated", v)
+	}
+	for _, v := range o.Added {
+		fmt.Fprintf(b, "%-8s %s\n", "added", v)
+	}
+	for _, v := range o.Kept {
+		fmt.Fprintf(b, "%-8s %s\n", "kept", v)
+	}
+	for _, v := range o.Outdated {
+		fmt.Fprintf(b, "%-8s %s\n", "outdated", v)
+	}
+	for _, v := range o.Orphaned {
+		fmt.Fprintf(b, "%-8s %s\n", "orphaned", v)
+	}
+	return b.String()
+}
+
+//SetOpenAPI validates the OpenAPI document and records it in the project relative to its destination
+func (p *Project) SetOpenAPI(doc string) error {
+	/*
+	 * We will load the document and check that the code can be generated for it
+	 * Then we will record it relative to the destination
+	 */
+	d, err := openapi.Load(doc)
+	if err != nil {
+		return err
+	}
+	_, err = d.API()
+	if err != nil {
+		return fmt.Errorf("can't generate the routes of the OpenAPI document %s: %v", doc, err)
+	}
+	abs, err := filepath.Abs(doc)
+	if err != nil {
+		return err
+	}
+	dst, err := filepath.Abs(p.Destination)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(dst, abs)
+	if err != nil {
+		rel = abs
+	}
+	p.OpenAPI = filepath.ToSlash(rel)
+	return nil
+}
+
+//SyncOpenAPI generates the types of the requests and responses, the routes and the stubs of the operations in the OpenAPI document
+//into the project generated in the given directory. The document recorded in the project is used if none is given.
+//Else the given document is recorded in the project. The types and the routes are generated again on every run while the stubs are
+//only added for the operations which don't have one. So the handler bodies written in the stubs are left as they are.
+//The stubs of the operations which aren't in the document anymore are reported as orphaned.
+func SyncOpenAPI(dir, doc string) (*OpenAPISync, error) {
+	/*
+	 * We will read the project and its OpenAPI document
+	 * We will check that the generated code doesn't clash with the routes package
+	 * We will check the existing stubs and add the missing ones
+	 * We will find the stubs of the operations not in the document
+	 * We will generate the files
+	 * Then we will record the document in the project
+	 */
+	m, err := ReadMetadata(dir)
+	if err != nil {
+		return nil, err
+	}
+	p := m.Project
+	if len(doc) > 0 {
+		err = p.SetOpenAPI(doc)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(p.OpenAPI) == 0 {
+		return nil, errors.New("the project has no OpenAPI document. Give the document to generate the routes from")
+	}
+	file := filepath.FromSlash(p.OpenAPI)
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+	d, err := openapi.Load(file)
+	if err != nil {
+		return nil, err
+	}
+	a, err := d.API()
+	if err != nil {
+		return nil, fmt.Errorf("can't generate the routes of the OpenAPI document %s: %v", p.OpenAPI, err)
+	}
+	err = p.Template.Resolve()
+	if err != nil {
+		//error while resolving the template
+		fmt.Println("Error while resolving the template", p.Template, "of the project in", dir)
+		return nil, err
+	}
+
+	//checking the clashes with the routes package
+	files, err := parseRoutes(dir)
+	if err != nil {
+		return nil, err
+	}
+	delete(files, filepath.Join(dir, "routes", openAPIGenerated+".go"))
+	decls, routes := routesDecls(files)
+	for _, v := range a.Identifiers() {
+		if f, ok := decls[v]; ok {
+			return nil, fmt.Errorf("%s of the routes generated from the OpenAPI document is already declared in %s", v, f.File)
+		}
+	}
+	for _, r := range a.Routes {
+		if f, ok := routes[a.Version+" "+r.Pattern]; ok {
+			return nil, fmt.Errorf("route %s %s of the OpenAPI document already exists in %s", a.Version, r.Pattern, f)
+		}
+	}
+
+	//checking the existing stubs and adding the missing ones
+	result := &OpenAPISync{}
+	scaffolds := []scaffold{
+		{Template: "openapi_types.go.tmpl", Path: filepath.Join(openapi.Package, openAPIGenerated+".go"), Replace: true},
+		{Template: "openapi_routes.go.tmpl", Path: filepath.Join("routes", openAPIGenerated+".go"), Replace: true},
+		{Template: "openapi_routes_test.go.tmpl", Path: filepath.Join("routes", openAPIGenerated+"_test.go"), Replace: true},
+	}
+	generated := len(scaffolds)
+	operations := map[string]bool{}
+	for _, r := range a.Routes {
+		for _, e := range r.Endpoints {
+			operations[e.Name] = true
+			f, ok := decls[e.Name]
+			if !ok {
+				scaffolds = append(scaffolds, scaffold{Template: "openapi_stub.go.tmpl", Path: filepath.Join("routes", fileName(e.Name, "handler")+".go"), Endpoint: e})
+				continue
+			}
+			result.Kept = append(result.Kept, e.Name)
+			if f.Func == nil || signature(f.Func.Type) != e.Signature() {
+				result.Outdated = append(result.Outdated, fmt.Sprintf("%s in %s has to be %s", e.Name, f.File, e.Signature()))
+			}
+		}
+	}
+
+	//finding the stubs of the operations not in the document
+	for _, file := range sortedFiles(files) {
+		if !isStub(files[file]) || declaresAny(files[file], operations) {
+			continue
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return nil, err
+		}
+		result.Orphaned = append(result.Orphaned, rel)
+	}
+
+	//generating the files
+	written, err := p.addScaffolds(scaffoldData{Package: p.Package, API: a}, scaffolds)
+	if err != nil {
+		return nil, err
+	}
+	result.Generated, result.Added = written[:generated], written[generated:]
+
+	//recording the document
+	if p.OpenAPI != m.Project.OpenAPI {
+		m.Project.OpenAPI = p.OpenAPI
+		err = m.write(dir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+//routesDecl is a declaration in the routes package
+type routesDecl struct {
+	//File is the file having the declaration
+	File string
+	//Func is the declaration if it is a func
+	Func *ast.FuncDecl
+}
+
+//routesDecls returns the top level declarations in the given files of the routes package by their name
+//and the files having the route literals by their version and pattern
+func routesDecls(files map[string]*ast.File) (map[string]routesDecl, map[string]string) {
+	decls := map[string]routesDecl{}
+	routes := map[string]string{}
+	for _, file := range sortedFiles(files) {
+		f := files[file]
+		for _, d := range f.Decls {
+			switch v := d.(type) {
+			case *ast.FuncDecl:
+				if v.Recv == nil {
+					decls[v.Name.Name] = routesDecl{File: file, Func: v}
+				}
+			case *ast.GenDecl:
+				for _, s := range v.Specs {
+					switch spec := s.(type) {
+					case *ast.TypeSpec:
+						decls[spec.Name.Name] = routesDecl{File: file}
+					case *ast.ValueSpec:
+						for _, n := range spec.Names {
+							decls[n.Name] = routesDecl{File: file}
+						}
+					}
+				}
+			}
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			if c, ok := n.(*ast.CompositeLit); ok {
+				if pattern := routeLiteral(c, "Pattern"); len(pattern) > 0 {
+					routes[routeLiteral(c, "Version")+" "+pattern] = file
+				}
+			}
+			return true
+		})
+	}
+	return decls, routes
+}
+
+//isStub tells whether the parsed file of the routes package is a stub added by the sync from its header
+func isStub(f *ast.File) bool {
+	for _, c := range f.Comments {
+		if strings.Contains(c.Text(), stubHeader) {
+			return true
+		}
+	}
+	return false
+}
+
+//declaresAny tells whether the parsed file declares a func with any of the given names
+func declaresAny(f *ast.File, names map[string]bool) bool {
+	for _, d := range f.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Recv == nil && names[fn.Name.Name] {
+			return true
+		}
+	}
+	return false
+}
+
+//signature returns the signature of the func type like func(context.Context, *http.Request) error
+func signature(f *ast.FuncType) string {
+	fields := func(l *ast.FieldList) []string {
+		list := []string{}
+		if l == nil {
+			return list
+		}
+		for _, v := range l.List {
+			t := types.ExprString(v.Type)
+			for i := 0; i < len(v.Names) || i == 0; i++ {
+				list = append(list, t)
+			}
+		}
+		return list
+	}
+	results := fields(f.Results)
+	s := strings.Join(results, ", ")
+	if len(results) > 1 {
+		s = "(" + s + ")"
+	}
+	return "func(" + strings.Join(fields(f.Params), ", ") + ") " + s
+}
@@ -0,0 +1,276 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/project"
+)
+
+/*
+ * This file contains the tests written for the source code in openapi.go
+ */
+
+//petStore is the OpenAPI document in the testdata of the openapi package
+var petStore = filepath.Join("..", "openapi", "testdata", "petstore.yaml")
+
+//petStoreStubs are the stubs of the operations of the pet store
+var petStoreStubs = []string{"routes/get_health_handler.go", "routes/list_pets_handler.go", "routes/create_pet_handler.go", "routes/get_pet_handler.go",
+	"routes/update_pet_handler.go", "routes/delete_pet_handler.go"}
+
+//TestSyncOpenAPI generates the routes of the pet store in a project and syncs them again after
+//implementing a stub and changing the document
+func TestSyncOpenAPI(t *testing.T) {
+	/*
+	 * We will generate the project with the document
+	 * We will check the generated files and the recorded document
+	 * Then we will sync again after implementing a stub and changing the document
+	 */
+	project.Templates = os.DirFS(templatesDir)
+	dst := filepath.Join(t.TempDir(), "orders")
+	p := testProject(dst)
+	p.Components = &project.Components{}
+	doc := filepath.Join(dst, "api.yaml")
+	b, err := ioutil.ReadFile(petStore)
+	if err == nil {
+		err = os.MkdirAll(dst, 0755)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(doc, string(b))
+	err = p.SetOpenAPI(doc)
+	if err != nil {
+		t.Fatal("Didn't expect an error. Got one", err)
+	}
+	if p.OpenAPI != "api.yaml" {
+		t.Error("Expected the document relative to the project. Got", p.OpenAPI)
+	}
+	err = p.Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//generating the routes
+	s, err := project.SyncOpenAPI(dst, "")
+	if err != nil {
+		t.Fatal("Didn't expect an error. Got one", err)
+	}
+	generated := []string{"api/openapi_gen.go", "routes/openapi_gen.go", "routes/openapi_gen_test.go"}
+	if !reflect.DeepEqual(slashes(s.Generated), generated) || !reflect.DeepEqual(slashes(s.Added), petStoreStubs) {
+		t.Error("Expected the generated files", generated, "and the stubs", petStoreStubs, "Got", s.Generated, s.Added)
+	}
+	checkPlaceholders(t, dst)
+
+	//implementing a stub and changing the document
+	stub := filepath.Join(dst, "routes", "get_health_handler.go")
+	original := readFile(t, stub)
+	implemented := strings.Replace(original, `&StatusError{Status: http.StatusNotImplemented, Message: "GetHealth is not implemented"}`, "nil", 1)
+	if implemented == original {
+		t.Fatal("Expected the stub of GetHealth to return the not implemented error. Got", original)
+	}
+	writeFile(stub, implemented)
+	changed := strings.Replace(string(b), "      requestBody:\n        $ref: '#/components/requestBodies/NewPet'\n", "", 1)
+	changed = strings.Replace(changed, "paths:\n", "paths:\n  /owners:\n    get:\n      operationId: listOwners\n      responses:\n        '200':\n          description: Owners\n", 1)
+	writeFile(doc, changed)
+
+	//syncing again
+	fmt.Println("Testing the sync of the changed document")
+	s, err = project.SyncOpenAPI(dst, "")
+	if err != nil {
+		t.Fatal("Didn't expect an error. Got one", err)
+	}
+	if !reflect.DeepEqual(slashes(s.Added), []string{"routes/list_owners_handler.go"}) {
+		t.Error("Expected only the stub of the new operation to be added. Got", s.Added)
+	}
+	if len(s.Kept) != len(petStoreStubs) {
+		t.Error("Expected the existing stubs to be kept. Got", s.Kept)
+	}
+	if len(s.Outdated) != 1 || !strings.HasPrefix(s.Outdated[0], "CreatePet") {
+		t.Error("Expected CreatePet without the body to be outdated. Got", s.Outdated)
+	}
+	if readFile(t, stub) != implemented {
+		t.Error("Expected the implemented stub to be left as it is")
+	}
+}
+
+//TestSyncOpenAPIOrphaned syncs the routes of the pet store, renames and removes operations in the document and syncs again
+//to check that the stubs of the operations not in the document anymore are reported
+func TestSyncOpenAPIOrphaned(t *testing.T) {
+	project.Templates = os.DirFS(templatesDir)
+	dst := filepath.Join(t.TempDir(), "orders")
+	p := testProject(dst)
+	p.Components = &project.Components{}
+	err := p.Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := filepath.Join(t.TempDir(), "api.yaml")
+	b, err := ioutil.ReadFile(petStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(doc, string(b))
+	s, err := project.SyncOpenAPI(dst, doc)
+	if err != nil {
+		t.Fatal("Didn't expect an error. Got one", err)
+	}
+	if len(s.Orphaned) != 0 {
+		t.Error("Expected no orphaned stubs. Got", s.Orphaned)
+	}
+
+	//renaming and removing the operations
+	changed := strings.Replace(string(b), "operationId: deletePet", "operationId: removePet", 1)
+	changed = strings.Replace(changed, "  /health:\n    get:\n      responses:\n        '200':\n          description: The server is up\n", "", 1)
+	if changed == string(b) {
+		t.Fatal("Expected the operations to be renamed and removed in the document")
+	}
+	writeFile(doc, changed)
+	fmt.Println("Testing the sync of the renamed and removed operations")
+	s, err = project.SyncOpenAPI(dst, "")
+	if err != nil {
+		t.Fatal("Didn't expect an error. Got one", err)
+	}
+	if !reflect.DeepEqual(slashes(s.Added), []string{"routes/remove_pet_handler.go"}) {
+		t.Error("Expected the stub of the renamed operation to be added. Got", s.Added)
+	}
+	orphaned := []string{"routes/delete_pet_handler.go", "routes/get_health_handler.go"}
+	if !reflect.DeepEqual(slashes(s.Orphaned), orphaned) {
+		t.Error("Expected the orphaned stubs", orphaned, "Got", s.Orphaned)
+	}
+	if !strings.Contains(s.String(), "orphaned routes/delete_pet_handler.go") {
+		t.Error("Expected the orphaned stubs to be listed. Got\n", s)
+	}
+}
+
+//TestSyncOpenAPIFileNames checks that the stubs of the operations named like tests or os specific files are part of the build
+func TestSyncOpenAPIFileNames(t *testing.T) {
+	project.Templates = os.DirFS(templatesDir)
+	dst := filepath.Join(t.TempDir(), "orders")
+	p := testProject(dst)
+	p.Components = &project.Components{}
+	err := p.Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := filepath.Join(t.TempDir(), "api.yaml")
+	writeFile(doc, "openapi: 3.0.0\npaths:\n  /tests:\n    post: {operationId: runTest}\n  /windows:\n    get: {operationId: listWindows}\n"+
+		"  /builds:\n    get: {operationId: buildArm64}\n")
+	s, err := project.SyncOpenAPI(dst, doc)
+	if err != nil {
+		t.Fatal("Didn't expect an error. Got one", err)
+	}
+	expected := []string{"routes/build_arm64_handler.go", "routes/run_test_handler.go", "routes/list_windows_handler.go"}
+	if !reflect.DeepEqual(slashes(s.Added), expected) {
+		t.Error("Expected the stubs", expected, "Got", s.Added)
+	}
+	checkBuildable(t, dst, slashes(s.Added))
+}
+
+var syncopenapierrortcs = []struct {
+	Name  string
+	Setup func(t *testing.T, dst string)
+	Doc   string
+}{
+	{"Project without a document", func(t *testing.T, dst string) {}, ""},
+	{"Missing document", func(t *testing.T, dst string) {}, "missing.yaml"},
+	{
+		"Route already in the project",
+		func(t *testing.T, dst string) {
+			_, err := project.AddRoute(dst, project.RouteSpec{Version: "v2", Pattern: "/pets", Register: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+		},
+		petStore,
+	},
+	{
+		"Generated identifier already in the project",
+		func(t *testing.T, dst string) {
+			writeFile(filepath.Join(dst, "routes", "errors.go"), "package routes\n\ntype StatusError struct{}\n")
+		},
+		petStore,
+	},
+}
+
+//TestSyncOpenAPIErrors checks that the routes aren't generated if they clash with the project
+func TestSyncOpenAPIErrors(t *testing.T) {
+	project.Templates = os.DirFS(templatesDir)
+	for _, v := range syncopenapierrortcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			dst := filepath.Join(t.TempDir(), "orders")
+			p := testProject(dst)
+			err := p.Setup()
+			if err != nil {
+				t.Fatal(err)
+			}
+			v.Setup(t, dst)
+			_, err = project.SyncOpenAPI(dst, v.Doc)
+			if err == nil {
+				t.Fatal("Expected an error. Got none.")
+			}
+			if _, err := os.Stat(filepath.Join(dst, "routes", "openapi_gen.go")); !os.IsNotExist(err) {
+				t.Error("Expected the routes not to be generated")
+			}
+		})
+	}
+}
+
+//TestOpenAPIVariants generates the routes of the pet store in the projects with all and none of the components
+//and checks that they vet, build and pass their tests
+func TestOpenAPIVariants(t *testing.T) {
+	project.Templates = os.DirFS(templatesDir)
+	env, build := harnessEnv(t)
+	for _, c := range []*project.Components{project.AllComponents(), {}} {
+		c := c
+		t.Run(strings.Join(append(c.Names(), "openapi"), "+"), func(t *testing.T) {
+			t.Parallel()
+			dst := filepath.Join(t.TempDir(), "orders")
+			p := testProject(dst)
+			p.Components = c
+			err := p.SetOpenAPI(petStore)
+			if err == nil {
+				err = p.Setup()
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = project.SyncOpenAPI(dst, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPlaceholders(t, dst)
+			if build {
+				buildVariant(t, p, env)
+			}
+		})
+	}
+}
+
+//readFile returns the content of the file failing the test if it can't be read
+func readFile(t *testing.T, file string) string {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+//slashes returns the paths with slashes as the separator
+func slashes(paths []string) []string {
+	s := []string{}
+	for _, v := range paths {
+		s = append(s, filepath.ToSlash(v))
+	}
+	return s
+}
@@ -0,0 +1,260 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+/*
+ * This file contains the definitions of the generation plan. A plan lists the changes the project setup
+ * would make in the destination without touching it.
+ */
+
+//PlannedFile is a file that would be written while setting up the project
+type PlannedFile struct {
+	//Path is the path of the file relative to the project destination
+	Path string
+	//Exists indicates that the file already exists in the destination and would be overwritten
+	Exists bool
+	//Kept indicates that the file already exists in the destination and would be kept as it is
+	Kept bool
+	//Conflict indicates that the file already exists in the destination and the setup would fail on it with a ConflictError
+	Conflict bool
+	//Refactors are the refactors that would be applied to the file
+	Refactors []PlannedRefactor
+	//Diff is the unified diff between the existing file in the destination and the rendered file
+	Diff string
+}
+
+//PlannedRefactor is a refactor that would be applied to a file while setting up the project
+type PlannedRefactor struct {
+	//Name of the refactor
+	Name string
+	//Find is the string to be found by the refactor. It is empty for the refactors which don't find and replace
+	Find string
+	//Matches is the number of matches of the string to be found in the file
+	Matches int
+}
+
+//Plan is the list of changes the project setup would make in the destination
+type Plan struct {
+	//Destination in which the project would be set up
+	Destination string
+	//Files that would be written in the destination
+	Files []PlannedFile
+}
+
+//Plan renders the project into a temporary directory and returns the changes that the setup would
+//make in the project destination along with the metadata of the project and the files generated from
+//its OpenAPI document. The existing files on which the setup would fail are marked as conflicts.
+//Nothing is written to the project destination.
+func (p Project) Plan() (*Plan, error) {
+	/*
+	 * We will resolve the template and validate the project
+	 * We will create a temporary directory for rendering the project
+	 * Then we will render the project in the temporary directory
+	 * We will find the conflicts if the setup fails on them
+	 * Then we will compare each rendered source and the metadata with the one in the destination
+	 * Then we will generate the routes from the OpenAPI document and compare them
+	 */
+	err := p.Template.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	err = p.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	//creating the temporary directory
+	tmp, err := ioutil.TempDir("", "web-starter-plan")
+	if err != nil {
+		//error while creating the temporary directory
+		fmt.Println("Error while creating the temporary directory for planning the project", p.Name)
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	//rendering the project in the temporary directory
+	err = (&p).InitSources()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.render(tmp)
+	if err != nil {
+		//error while rendering the project
+		fmt.Println("Error while rendering the project for planning", p.Name)
+		return nil, err
+	}
+
+	//finding the conflicts
+	conflicts := map[string]bool{}
+	if p.OnConflict == "" || p.OnConflict == Fail {
+		var cErr ConflictError
+		err = p.checkConflicts()
+		if errors.As(err, &cErr) {
+			for _, v := range cErr.Files {
+				conflicts[v] = true
+			}
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	//comparing the rendered sources and the metadata with the destination
+	plan := &Plan{Destination: p.Destination}
+	for _, v := range p.Sources {
+		f, err := p.planPath(tmp, v.RelativePath(), conflicts)
+		if err != nil {
+			//error while comparing the rendered source with the destination
+			fmt.Println("Error while planning the source", (&v).Name())
+			return nil, err
+		}
+		for _, r := range v.Refactors {
+			f.Refactors = append(f.Refactors, PlannedRefactor{Name: r.String(), Find: r.Find, Matches: r.Matches})
+		}
+		plan.Files = append(plan.Files, *f)
+	}
+	f, err := p.planPath(tmp, MetadataFile, conflicts)
+	if err != nil {
+		//error while comparing the metadata with the destination
+		fmt.Println("Error while planning the metadata of the project", p.Name)
+		return nil, err
+	}
+	plan.Files = append(plan.Files, *f)
+
+	//generating the routes from the OpenAPI document
+	if len(p.OpenAPI) == 0 {
+		return plan, nil
+	}
+	files, err := p.planOpenAPI(tmp)
+	if err != nil {
+		//error while generating the routes from the OpenAPI document
+		fmt.Println("Error while planning the routes of the OpenAPI document", p.OpenAPI)
+		return nil, err
+	}
+	for _, v := range files {
+		f, err := planFile(filepath.Join(tmp, v), filepath.Join(p.Destination, v), tmp)
+		if err != nil {
+			return nil, err
+		}
+		plan.Files = append(plan.Files, *f)
+	}
+	return plan, nil
+}
+
+//planPath compares the file at the given path relative to the destination rendered in the given directory with the
+//one in the destination. The file is marked as kept if the existing one has to be kept and as a conflict if it is in the conflicts.
+func (p Project) planPath(tmp, rel string, conflicts map[string]bool) (*PlannedFile, error) {
+	if p.keep(rel) {
+		return &PlannedFile{Path: rel, Exists: true, Kept: true}, nil
+	}
+	f, err := planFile(filepath.Join(tmp, rel), filepath.Join(p.Destination, rel), tmp)
+	if err != nil {
+		return nil, err
+	}
+	f.Conflict = conflicts[rel]
+	return f, nil
+}
+
+//planOpenAPI generates the routes of the OpenAPI document of the project in the project rendered in the given directory.
+//The files of the routes package already in the destination are copied into the directory first so that the existing stubs
+//are kept like in the destination. It returns the files generated and added relative to the directory.
+func (p Project) planOpenAPI(tmp string) ([]string, error) {
+	/*
+	 * We will write the metadata if the existing one is kept
+	 * We will copy the files of the routes package in the destination which aren't rendered
+	 * Then we will generate the routes from the document resolved against the destination
+	 */
+	if p.keep(MetadataFile) {
+		err := p.writeMetadata(tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	//copying the files of the routes package
+	routes, err := ioutil.ReadDir(filepath.Join(p.Destination, "routes"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, v := range routes {
+		if v.IsDir() || !strings.HasSuffix(v.Name(), ".go") {
+			continue
+		}
+		dst := filepath.Join(tmp, "routes", v.Name())
+		if _, err := os.Stat(dst); err == nil {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(p.Destination, "routes", v.Name()))
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(dst), 0755)
+		}
+		if err == nil {
+			err = ioutil.WriteFile(dst, b, 0644)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	//generating the routes
+	doc := filepath.FromSlash(p.OpenAPI)
+	if !filepath.IsAbs(doc) {
+		doc = filepath.Join(p.Destination, doc)
+	}
+	s, err := SyncOpenAPI(tmp, doc)
+	if err != nil {
+		return nil, err
+	}
+	return append(s.Generated, s.Added...), nil
+}
+
+//planFile compares the rendered file with the existing one and returns it with the path relative to the given directory
+func planFile(rendered, existing, tmp string) (*PlannedFile, error) {
+	/*
+	 * We will read the rendered file
+	 * Then we will read the existing file if any
+	 * Then we will diff them
+	 */
+	//reading the rendered file
+	rel, err := filepath.Rel(tmp, rendered)
+	if err != nil {
+		return nil, err
+	}
+	newC, err := ioutil.ReadFile(rendered)
+	if err != nil {
+		return nil, err
+	}
+
+	//reading the existing file
+	f := &PlannedFile{Path: rel}
+	from, oldLines := "/dev/null", []string{}
+	oldC, err := ioutil.ReadFile(existing)
+	if err == nil {
+		f.Exists = true
+		from, oldLines = "a/"+filepath.ToSlash(rel), difflib.SplitLines(string(oldC))
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	//diffing the files
+	f.Diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        oldLines,
+		B:        difflib.SplitLines(string(newC)),
+		FromFile: from,
+		ToFile:   "b/" + filepath.ToSlash(rel),
+		Context:  3,
+	})
+	return f, err
+}
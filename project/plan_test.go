@@ -0,0 +1,142 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/project"
+)
+
+/*
+ * This file contains the tests written for the source code in plan.go
+ */
+
+var plantcs = []struct {
+	Name     string
+	Policy   project.ConflictPolicy
+	OpenAPI  bool
+	Setup    func(dst string)
+	Expected map[string]string
+	Missing  []string
+}{
+	{
+		"New destination",
+		project.Fail,
+		false,
+		func(dst string) {},
+		map[string]string{"main.go": "create", project.MetadataFile: "create"},
+		[]string{"routes/openapi_gen.go"},
+	},
+	{
+		"Existing files conflict",
+		project.Fail,
+		false,
+		func(dst string) {
+			writeFile(filepath.Join(dst, "main.go"), "existing")
+			writeFile(filepath.Join(dst, project.MetadataFile), "{}")
+		},
+		map[string]string{"main.go": "conflict", project.MetadataFile: "conflict", "README.md": "create"},
+		nil,
+	},
+	{
+		"Existing files are overwritten",
+		project.Overwrite,
+		false,
+		func(dst string) {
+			writeFile(filepath.Join(dst, "main.go"), "existing")
+		},
+		map[string]string{"main.go": "overwrite", project.MetadataFile: "create"},
+		nil,
+	},
+	{
+		"Existing files are kept",
+		project.SkipExisting,
+		false,
+		func(dst string) {
+			writeFile(filepath.Join(dst, project.MetadataFile), "{}")
+		},
+		map[string]string{"main.go": "create", project.MetadataFile: "keep"},
+		nil,
+	},
+	{
+		"Routes of the OpenAPI document",
+		project.Fail,
+		true,
+		func(dst string) {
+			err := os.MkdirAll(filepath.Join(dst, "routes"), 0755)
+			if err != nil {
+				fmt.Println("Error while creating the routes directory", err)
+			}
+			writeFile(filepath.Join(dst, "routes", "pets.go"), "package routes\n\nfunc ListPets() {}\n")
+		},
+		map[string]string{
+			"api/openapi_gen.go":         "create",
+			"routes/openapi_gen.go":      "create",
+			"routes/openapi_gen_test.go": "create",
+			"routes/get_pet_handler.go":  "create",
+			project.MetadataFile:         "create",
+		},
+		[]string{"routes/list_pets_handler.go"},
+	},
+}
+
+//TestPlan checks the files planned for the project with the conflict policies and the OpenAPI document
+func TestPlan(t *testing.T) {
+	project.Templates = os.DirFS(templatesDir)
+	for _, v := range plantcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			dst := filepath.Join(t.TempDir(), "orders")
+			err := os.Mkdir(dst, 0755)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v.Setup(dst)
+			p := testProject(dst)
+			p.OnConflict = v.Policy
+			if v.OpenAPI {
+				err = p.SetOpenAPI(petStore)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			plan, err := p.Plan()
+			if err != nil {
+				t.Fatal("Didn't expect an error. Got one", err)
+			}
+			actions := map[string]string{}
+			for _, f := range plan.Files {
+				actions[filepath.ToSlash(f.Path)] = plannedAction(f)
+			}
+			for path, action := range v.Expected {
+				if actions[path] != action {
+					t.Errorf("Expected %s to be planned as %s. Got %q", path, action, actions[path])
+				}
+			}
+			for _, path := range v.Missing {
+				if _, ok := actions[path]; ok {
+					t.Error("Expected", path, "not to be planned. Got", actions[path])
+				}
+			}
+		})
+	}
+}
+
+//plannedAction returns the action of the planned file like it is printed by the dry run
+func plannedAction(f project.PlannedFile) string {
+	switch {
+	case f.Conflict:
+		return "conflict"
+	case f.Kept:
+		return "keep"
+	case f.Exists:
+		return "overwrite"
+	}
+	return "create"
+}
@@ -8,6 +8,9 @@ package project
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/cuttle-ai/web-starter/generate"
 )
@@ -23,6 +26,8 @@ type LicenseType string
 const (
 	//AGPL3 type license
 	AGPL3 LicenseType = "AGPL-3"
+	//APACHE2 type license
+	APACHE2 LicenseType = "Apache-2"
 	//BSD2 type license
 	BSD2 LicenseType = "BSD-2"
 	//BSD3 type license
@@ -33,46 +38,65 @@ const (
 	GPL2 LicenseType = "GPL-2"
 	//GPL3 type license
 	GPL3 LicenseType = "GPL-3"
+	//LGPL3 type license
+	LGPL3 LicenseType = "LGPL-3"
 	//MIT type license
 	MIT LicenseType = "MIT"
+	//MPL2 type license
+	MPL2 LicenseType = "MPL-2"
 	//UNLICENSED type license
 	UNLICENSED LicenseType = "UNLICENSED"
 )
 
+//LicenseTypes are the types of license supported by the built in template
+var LicenseTypes = []LicenseType{AGPL3, APACHE2, BSD2, BSD3, CLOSED, GPL2, GPL3, LGPL3, MIT, MPL2, UNLICENSED}
+
 //License gives info about the license
 type License struct {
 	//Type is the type of license
-	Type LicenseType
+	Type LicenseType `json:"type" yaml:"type"`
 	//Year of the license validity
-	Year string
+	Year string `json:"year" yaml:"year"`
 	//Organisation that issed the license
-	Organisation string
+	Organisation string `json:"organisation" yaml:"organisation"`
 }
 
 //Project struct lists the information about the project
 type Project struct {
 	//Name of the project
-	Name string
+	Name string `json:"name" yaml:"name"`
 	//Description of the project
-	Description string
+	Description string `json:"description" yaml:"description"`
 	//Author information for the project
-	Author Author
+	Author Author `json:"author" yaml:"author"`
 	//Destination target for setting up the boilerplate code
-	Destination string
+	Destination string `json:"destination,omitempty" yaml:"destination,omitempty"`
 	//Package is the package path to be used by the project
-	Package string
+	Package string `json:"package" yaml:"package"`
 	//Sources is the list of sources with refactors in the boilerplate code
-	Sources []generate.Source
+	Sources []generate.Source `json:"-" yaml:"-"`
 	//License is the license to be provided for the project
-	License License
+	License License `json:"license" yaml:"license"`
+	//Template is the template from which the project is generated
+	Template Template `json:"template" yaml:"template"`
+	//Components are the optional components chosen for the project. All of them are chosen if it is nil
+	Components *Components `json:"components,omitempty" yaml:"components,omitempty"`
+	//LicensesDir is the directory having additional license templates as <type>/LICENSE
+	LicensesDir string `json:"licensesDir,omitempty" yaml:"licensesDir,omitempty"`
+	//OpenAPI is the OpenAPI document of the project relative to its destination from which its routes are generated
+	OpenAPI string `json:"openapi,omitempty" yaml:"-"`
+	//OnConflict tells how the files already existing in the destination have to be handled. Setup fails by default
+	OnConflict ConflictPolicy `json:"-" yaml:"-"`
+	//Keep has the files relative to the destination which have to be kept as it is if they already exist
+	Keep map[string]bool `json:"-" yaml:"-"`
 }
 
 //Author refers to the initial project author
 type Author struct {
 	//Name of the author
-	Name string
+	Name string `json:"name" yaml:"name"`
 	//Email of the author
-	Email string
+	Email string `json:"email" yaml:"email"`
 }
 
 //String is the stringer implementation of the author
@@ -81,20 +105,97 @@ func (a Author) String() string {
 }
 
 //Setup will setup a project with boilerplate code.
+//The sources are rendered in a staging directory next to the destination and are moved into the
+//destination only if all the sources and their refactors succeed. If anything fails, the destination
+//is left as it was and the returned error names the failing source and refactor.
 func (p Project) Setup() error {
 	/*
+	 * We will resolve the template and validate the project
 	 * We will init the project sources
+	 * If the conflicts have to fail the setup, we will check for them
+	 * We will create the staging directory
+	 * Then will generate the code in the staging directory
+	 * Then we will move the generated code into the destination
+	 */
+	err := p.Template.Resolve()
+	if err != nil {
+		return err
+	}
+	err = p.Validate()
+	if err != nil {
+		return err
+	}
+	err = (&p).InitSources()
+	if err != nil {
+		return err
+	}
+	if p.OnConflict == "" || p.OnConflict == Fail {
+		err = p.checkConflicts()
+		if err != nil {
+			return err
+		}
+	}
+
+	//creating the staging directory in the same parent as the destination so that it can be renamed into place
+	parent := filepath.Dir(p.Destination)
+	err = os.MkdirAll(parent, 0775)
+	if err != nil {
+		//Error while creating the parent directory of the destination
+		fmt.Println("Error while creating the parent directory of the destination", p.Destination)
+		return err
+	}
+	staging, err := ioutil.TempDir(parent, "."+filepath.Base(p.Destination)+"-web-starter-")
+	if err != nil {
+		//Error while creating the staging directory
+		fmt.Println("Error while creating the staging directory for the project", p.Name)
+		return err
+	}
+	defer os.RemoveAll(staging)
+
+	//generating the code in the staging directory
+	files, err := p.render(staging)
+	if err != nil {
+		//Error while generating the code
+		fmt.Println("Nothing is written to the destination", p.Destination)
+		return err
+	}
+
+	//moving the generated code into the destination
+	return commit(staging, p.Destination, files)
+}
+
+//render will generate the sources of the project in the given directory except the ones whose existing
+//files in the destination have to be kept. It returns the generated files relative to the directory.
+//The sources of the project have to be initialized before calling it.
+func (p Project) render(dir string) ([]string, error) {
+	/*
 	 * We will iterate through the sources
-	 * Then will generate the code
+	 * Then will generate the code unless the existing file has to be kept
+	 * Then we will write the metadata of the project unless the existing one has to be kept
 	 */
-	(&p).InitSources()
+	files := []string{}
 	for _, v := range p.Sources {
-		err := v.Generate(p.Destination)
+		if p.keep(v.RelativePath()) {
+			fmt.Println("Keeping the existing file", v.RelativePath())
+			continue
+		}
+		err := v.Generate(dir)
 		if err != nil {
 			//Error while generating the source
 			fmt.Println("Error while generating the source while setting up", (&v).Name(), "in the project", p.Name)
-			return err
+			return nil, err
 		}
+		files = append(files, v.RelativePath())
+	}
+
+	//writing the metadata of the project
+	if p.keep(MetadataFile) {
+		fmt.Println("Keeping the existing file", MetadataFile)
+		return files, nil
+	}
+	err := p.writeMetadata(dir)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return append(files, MetadataFile), nil
 }
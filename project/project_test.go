@@ -0,0 +1,204 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/cuttle-ai/web-starter/generate"
+	"github.com/cuttle-ai/web-starter/project"
+)
+
+/*
+ * This file contains the tests written for the source code in project.go
+ */
+
+//templatesDir is the root of the web-starter source having the boilerplate and license templates
+var templatesDir = ".."
+
+func testProject(dst string) project.Project {
+	return project.Project{
+		Name:        "Orders",
+		Description: "Order management service",
+		Author:      project.Author{Name: "Jane", Email: "jane@example.com"},
+		Destination: dst,
+		Package:     "github.com/jane/orders",
+		License:     project.License{Type: project.MIT, Year: "2019", Organisation: "Example Inc"},
+	}
+}
+
+var setuptcs = []struct {
+	Name      string
+	Templates func() fs.FS
+	Policy    project.ConflictPolicy
+	Setup     func(dst string)
+	Validate  func(dst string, err error) (string, bool)
+}{
+	{
+		"Generate into a new destination",
+		nil,
+		project.Fail,
+		func(dst string) {},
+		func(dst string, err error) (string, bool) {
+			if err != nil {
+				return "Didn't expect an error. Got " + err.Error(), false
+			}
+			if _, err := os.Stat(filepath.Join(dst, "routes", "response", "response.go")); err != nil {
+				return err.Error(), false
+			}
+			return "", true
+		},
+	},
+	{
+		"Conflicting files fail the setup",
+		nil,
+		project.Fail,
+		func(dst string) {
+			writeFile(filepath.Join(dst, "main.go"), "existing")
+		},
+		func(dst string, err error) (string, bool) {
+			var conflict project.ConflictError
+			if !errors.As(err, &conflict) || len(conflict.Files) != 1 || conflict.Files[0] != "main.go" {
+				return fmt.Sprint("Expected a conflict error for main.go. Got ", err), false
+			}
+			return unchanged(dst)
+		},
+	},
+	{
+		"Existing metadata fails the setup",
+		nil,
+		project.Fail,
+		func(dst string) {
+			writeFile(filepath.Join(dst, project.MetadataFile), `{"project":{"name":"Payments"}}`)
+		},
+		func(dst string, err error) (string, bool) {
+			var conflict project.ConflictError
+			if !errors.As(err, &conflict) || len(conflict.Files) != 1 || conflict.Files[0] != project.MetadataFile {
+				return fmt.Sprint("Expected a conflict error for ", project.MetadataFile, ". Got ", err), false
+			}
+			return "", true
+		},
+	},
+	{
+		"Existing metadata is kept with skip existing",
+		nil,
+		project.SkipExisting,
+		func(dst string) {
+			writeFile(filepath.Join(dst, project.MetadataFile), `{"project":{"name":"Payments"}}`)
+		},
+		func(dst string, err error) (string, bool) {
+			if err != nil {
+				return "Didn't expect an error. Got " + err.Error(), false
+			}
+			b, err := ioutil.ReadFile(filepath.Join(dst, project.MetadataFile))
+			if err != nil || string(b) != `{"project":{"name":"Payments"}}` {
+				return fmt.Sprint("Expected the existing metadata to be kept. Got ", string(b), err), false
+			}
+			return "", true
+		},
+	},
+	{
+		"Failing source leaves the destination as it was",
+		func() fs.FS {
+			return fstest.MapFS{
+				"manifest.yaml": &fstest.MapFile{Data: []byte(
+					"files:\n  - path: boilerplate/main.go\n  - path: boilerplate/.gitignore\n")},
+				"boilerplate/main.go": &fstest.MapFile{Data: []byte("package main\n")},
+			}
+		},
+		project.Overwrite,
+		func(dst string) {
+			writeFile(filepath.Join(dst, "main.go"), "existing")
+		},
+		func(dst string, err error) (string, bool) {
+			var gErr *generate.Error
+			if !errors.As(err, &gErr) || gErr.Source != "boilerplate/.gitignore" {
+				return fmt.Sprint("Expected the error to name the failing source boilerplate/.gitignore. Got ", err), false
+			}
+			return unchanged(dst)
+		},
+	},
+	{
+		"Failing move restores the destination",
+		nil,
+		project.Overwrite,
+		func(dst string) {
+			writeFile(filepath.Join(dst, "main.go"), "existing")
+			//config has to be a directory in the generated project
+			writeFile(filepath.Join(dst, "config"), "not a directory")
+		},
+		func(dst string, err error) (string, bool) {
+			if err == nil {
+				return "Expected an error while moving config/config.go. Got none", false
+			}
+			if _, err := os.Stat(filepath.Join(dst, "version")); !os.IsNotExist(err) {
+				return "Expected the version directory created by the setup to be removed", false
+			}
+			return unchanged(dst)
+		},
+	},
+}
+
+func writeFile(file, content string) {
+	err := ioutil.WriteFile(file, []byte(content), 0644)
+	if err != nil {
+		fmt.Println("Error while writing the file", file, err)
+	}
+}
+
+//unchanged validates that the main.go written by the test setup is left as it was and nothing else
+//is generated in the destination or next to it
+func unchanged(dst string) (string, bool) {
+	b, err := ioutil.ReadFile(filepath.Join(dst, "main.go"))
+	if err != nil {
+		return err.Error(), false
+	}
+	if string(b) != "existing" {
+		return "Expected main.go to be left as it was. Got " + string(b), false
+	}
+	if _, err := os.Stat(filepath.Join(dst, "README.md")); !os.IsNotExist(err) {
+		return "Expected README.md to be not generated", false
+	}
+	siblings, err := ioutil.ReadDir(filepath.Dir(dst))
+	if err != nil {
+		return err.Error(), false
+	}
+	if len(siblings) != 1 {
+		return fmt.Sprint("Expected the staging and backup directories to be removed. Got ", len(siblings), " entries"), false
+	}
+	return "", true
+}
+
+//TestSetup is the test suite for the Setup method of the Project
+func TestSetup(t *testing.T) {
+	for _, v := range setuptcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			project.Templates = os.DirFS(templatesDir)
+			if v.Templates != nil {
+				project.Templates = v.Templates()
+			}
+			dst := filepath.Join(t.TempDir(), "orders")
+			err := os.Mkdir(dst, 0755)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v.Setup(dst)
+			p := testProject(dst)
+			p.OnConflict = v.Policy
+			err = p.Setup()
+			if res, ok := v.Validate(dst, err); !ok {
+				t.Error("Test result validation failed", res)
+			}
+		})
+	}
+}
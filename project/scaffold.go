@@ -0,0 +1,467 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cuttle-ai/web-starter/generate"
+	"github.com/cuttle-ai/web-starter/openapi"
+)
+
+/*
+ * This file contains the generators adding routes, handlers and models to a generated project
+ * from the scaffolds of its template
+ */
+
+//ScaffoldsPath is the directory in the templates having the scaffolds of the generators
+const ScaffoldsPath = "scaffolds"
+
+//methods are the http methods supported by the generators with the names of their constants in net/http
+var methods = map[string]string{
+	"GET":     "MethodGet",
+	"HEAD":    "MethodHead",
+	"POST":    "MethodPost",
+	"PUT":     "MethodPut",
+	"PATCH":   "MethodPatch",
+	"DELETE":  "MethodDelete",
+	"OPTIONS": "MethodOptions",
+}
+
+//fieldTypes are the types of the model fields supported by the model generator with their go types
+var fieldTypes = map[string]string{
+	"string":  "string",
+	"bool":    "bool",
+	"int":     "int",
+	"int32":   "int32",
+	"int64":   "int64",
+	"uint":    "uint",
+	"float32": "float32",
+	"float64": "float64",
+	"time":    "time.Time",
+}
+
+//RouteSpec is a route to be added to a generated project
+type RouteSpec struct {
+	//Version is the api version of the route like v1
+	Version string
+	//Pattern is the url pattern of the route like /users
+	Pattern string
+	//Method is the http method handled by the route
+	Method string
+	//Handler is the name of the handler func. Defaults to the method and the pattern like GetUsers
+	Handler string
+	//Register adds the route to the routes of the project. Else only the handler is added
+	Register bool
+}
+
+//MethodConst returns the name of the constant of the method in net/http
+func (r RouteSpec) MethodConst() string {
+	return methods[r.Method]
+}
+
+//OtherMethodConst returns the name of the constant in net/http of a method other than the method of the route
+func (r RouteSpec) OtherMethodConst() string {
+	if r.Method == "GET" {
+		return methods["POST"]
+	}
+	return methods["GET"]
+}
+
+//ModelField is a field of a model
+type ModelField struct {
+	//Name of the field
+	Name string
+	//Type of the field. Any of string, bool, int, int32, int64, uint, float32, float64 or time
+	Type string
+}
+
+//GoType returns the go type of the field
+func (m ModelField) GoType() string {
+	return fieldTypes[m.Type]
+}
+
+//JSON returns the json name of the field
+func (m ModelField) JSON() string {
+	return generate.CamelCase(m.Name)
+}
+
+//ModelSpec is a gorm model to be added to a generated project along with its CRUD routes
+type ModelSpec struct {
+	//Name of the model like User
+	Name string
+	//Fields of the model other than the id and the timestamps of gorm.Model
+	Fields []ModelField
+}
+
+//Plural returns the plural of the model name like Users
+func (m ModelSpec) Plural() string {
+	n := m.Name
+	switch {
+	case strings.HasSuffix(n, "y") && len(n) > 1 && !strings.ContainsRune("aeiou", rune(n[len(n)-2])):
+		return n[:len(n)-1] + "ies"
+	case strings.HasSuffix(n, "s"), strings.HasSuffix(n, "x"), strings.HasSuffix(n, "ch"), strings.HasSuffix(n, "sh"):
+		return n + "es"
+	}
+	return n + "s"
+}
+
+//Label returns the model name in words to be used in the comments and messages like order item
+func (m ModelSpec) Label() string {
+	return strings.Join(generate.Words(m.Name), " ")
+}
+
+//UsesTime tells whether any field of the model is of the type time
+func (m ModelSpec) UsesTime() bool {
+	for _, v := range m.Fields {
+		if v.Type == "time" {
+			return true
+		}
+	}
+	return false
+}
+
+//ParseFields parses the fields of a model given as name:type pairs separated by commas like name:string,age:int
+func ParseFields(s string) ([]ModelField, error) {
+	fields := []ModelField{}
+	for _, v := range strings.Split(s, ",") {
+		if len(strings.TrimSpace(v)) == 0 {
+			continue
+		}
+		parts := strings.SplitN(v, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("field %s has to be given as name:type", v)
+		}
+		f := ModelField{Name: generate.PascalCase(parts[0]), Type: strings.ToLower(strings.TrimSpace(parts[1]))}
+		if !token.IsIdentifier(f.Name) {
+			return nil, fmt.Errorf("%s is not a valid field name", parts[0])
+		}
+		if _, ok := fieldTypes[f.Type]; !ok {
+			return nil, fmt.Errorf("type %s of the field %s isn't supported. Use any of string, bool, int, int32, int64, uint, float32, float64 or time", parts[1], parts[0])
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+//scaffoldData is the data against which the scaffolds are rendered
+type scaffoldData struct {
+	//Package is the package path of the project
+	Package string
+	//Route is the route being added
+	Route RouteSpec
+	//Model is the model being added
+	Model ModelSpec
+	//API is the code generated for the OpenAPI document of the project
+	API *openapi.API
+	//Endpoint is the operation of the OpenAPI document whose stub is being added
+	Endpoint *openapi.Endpoint
+}
+
+//scaffold is a file to be generated in the project from a scaffold of the template
+type scaffold struct {
+	//Template is the name of the scaffold in the scaffolds of the template
+	Template string
+	//Path is the path of the file relative to the project
+	Path string
+	//Replace replaces the file if it already exists. It is used for the files generated again on every run
+	Replace bool
+	//Endpoint is the operation of the OpenAPI document whose stub is rendered in the file
+	Endpoint *openapi.Endpoint
+}
+
+//AddRoute adds the handler of the route along with its test to the project generated in the given directory.
+//If the route has to be registered, the handler is added to the routes of the project in the init of its file.
+//It returns the files added relative to the project.
+func AddRoute(dir string, r RouteSpec) ([]string, error) {
+	/*
+	 * We will read the project from its metadata
+	 * We will validate the route and fill the defaults
+	 * We will check that the route and the handler don't exist already
+	 * Then we will generate the handler and its test
+	 */
+	p, err := scaffoldProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	err = r.validate()
+	if err != nil {
+		return nil, err
+	}
+	err = checkRoutes(dir, r)
+	if err != nil {
+		return nil, err
+	}
+	name := fileName(r.Handler, "handler")
+	return p.addScaffolds(scaffoldData{Package: p.Package, Route: r}, []scaffold{
+		{Template: "handler.go.tmpl", Path: filepath.Join("routes", name+".go")},
+		{Template: "handler_test.go.tmpl", Path: filepath.Join("routes", name+"_test.go")},
+	})
+}
+
+//AddModel adds the gorm model to the models of the project generated in the given directory along with
+//the CRUD routes of the model at the given pattern using the database connection of the app context.
+//The pattern defaults to the plural of the model name like /users. The project has to have the database component.
+//It returns the files added relative to the project.
+func AddModel(dir string, m ModelSpec, version, pattern string) ([]string, error) {
+	/*
+	 * We will read the project from its metadata and check that it has the database
+	 * We will validate the model and its route
+	 * We will check that the route and the handler don't exist already
+	 * Then we will generate the model, its CRUD routes and their test
+	 */
+	p, err := scaffoldProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	if p.Components != nil && !p.Components.Database {
+		return nil, errors.New("the project was generated without the database component. Models need the database connection of the app context")
+	}
+	m.Name = generate.PascalCase(m.Name)
+	if !token.IsIdentifier(m.Name) {
+		return nil, fmt.Errorf("%q is not a valid model name", m.Name)
+	}
+	if len(pattern) == 0 {
+		pattern = "/" + generate.KebabCase(m.Plural())
+	}
+	r := RouteSpec{Version: version, Pattern: pattern, Method: "GET", Handler: m.Plural(), Register: true}
+	err = r.validate()
+	if err != nil {
+		return nil, err
+	}
+	err = checkRoutes(dir, r)
+	if err != nil {
+		return nil, err
+	}
+	name := fileName(m.Name, "model")
+	plural := fileName(m.Plural(), "handler")
+	return p.addScaffolds(scaffoldData{Package: p.Package, Route: r, Model: m}, []scaffold{
+		{Template: "model.go.tmpl", Path: filepath.Join("models", name+".go")},
+		{Template: "crud.go.tmpl", Path: filepath.Join("routes", plural+".go")},
+		{Template: "crud_test.go.tmpl", Path: filepath.Join("routes", plural+"_test.go")},
+	})
+}
+
+//scaffoldProject reads the project generated in the given directory from its metadata and resolves its template
+func scaffoldProject(dir string) (*Project, error) {
+	m, err := ReadMetadata(dir)
+	if err != nil {
+		return nil, err
+	}
+	p := m.Project
+	err = p.Template.Resolve()
+	if err != nil {
+		//error while resolving the template
+		fmt.Println("Error while resolving the template", p.Template, "of the project in", dir)
+		return nil, err
+	}
+	return &p, nil
+}
+
+//validate validates the route and fills in the defaults of the handler name and the version
+func (r *RouteSpec) validate() error {
+	r.Method = strings.ToUpper(r.Method)
+	if len(r.Method) == 0 {
+		r.Method = "GET"
+	}
+	if _, ok := methods[r.Method]; !ok {
+		return fmt.Errorf("method %s isn't supported. Use any of GET, HEAD, POST, PUT, PATCH, DELETE or OPTIONS", r.Method)
+	}
+	if !strings.HasPrefix(r.Pattern, "/") || strings.ContainsAny(r.Pattern, "\"` \t\n") {
+		return fmt.Errorf("%q is not a valid route pattern. It has to start with a /", r.Pattern)
+	}
+	if len(r.Version) == 0 {
+		r.Version = "v1"
+	}
+	if len(r.Handler) == 0 {
+		r.Handler = generate.PascalCase(strings.ToLower(r.Method) + " " + r.Pattern)
+		if r.Handler == generate.PascalCase(r.Method) {
+			r.Handler += "Root"
+		}
+	}
+	if !token.IsIdentifier(r.Handler) || !ast.IsExported(r.Handler) {
+		return fmt.Errorf("%q is not a valid exported handler name", r.Handler)
+	}
+	return nil
+}
+
+//checkRoutes checks that neither the handler nor the pattern of the route in its version are already in the routes of the project.
+//The http mux of the project doesn't allow a pattern to be registered twice. So another method of a pattern has to be
+//handled by its existing handler.
+func checkRoutes(dir string, r RouteSpec) error {
+	/*
+	 * We will parse the go files in the routes package
+	 * Then we will check the names of the funcs and the route literals
+	 */
+	files, err := parseRoutes(dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range sortedFiles(files) {
+		f := files[file]
+		var found error
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch v := n.(type) {
+			case *ast.FuncDecl:
+				if v.Recv == nil && v.Name.Name == r.Handler {
+					found = fmt.Errorf("handler %s already exists in %s", r.Handler, file)
+				}
+			case *ast.CompositeLit:
+				if r.Register && routeLiteral(v, "Version") == r.Version && routeLiteral(v, "Pattern") == r.Pattern {
+					found = fmt.Errorf("route %s %s already exists in %s. Handle the %s method in its handler", r.Version, r.Pattern, file, r.Method)
+				}
+			}
+			return found == nil
+		})
+		if found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+//parseRoutes parses the go files of the routes package of the project in the given directory other than its tests.
+//It returns the parsed files by their path.
+func parseRoutes(dir string) (map[string]*ast.File, error) {
+	fset := token.NewFileSet()
+	files, err := filepath.Glob(filepath.Join(dir, "routes", "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	parsed := map[string]*ast.File{}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			//error while parsing the routes
+			fmt.Println("Error while parsing the routes in", file)
+			return nil, err
+		}
+		parsed[file] = f
+	}
+	return parsed, nil
+}
+
+//sortedFiles returns the paths of the parsed files sorted
+func sortedFiles(files map[string]*ast.File) []string {
+	paths := []string{}
+	for k := range files {
+		paths = append(paths, k)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+//routeLiteral returns the string value of the given key in the composite literal if it is a string literal
+func routeLiteral(c *ast.CompositeLit, key string) string {
+	for _, v := range c.Elts {
+		kv, ok := v.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if k, ok := kv.Key.(*ast.Ident); !ok || k.Name != key {
+			continue
+		}
+		if b, ok := kv.Value.(*ast.BasicLit); ok && b.Kind == token.STRING {
+			s, err := strconv.Unquote(b.Value)
+			if err == nil {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+//addScaffolds renders the given scaffolds of the template of the project against the data and writes them into the project.
+//Nothing is written if any of the files which aren't to be replaced already exist or any of the scaffolds fail to render.
+func (p *Project) addScaffolds(data scaffoldData, scaffolds []scaffold) ([]string, error) {
+	/*
+	 * We will check that the files don't exist already
+	 * We will render the scaffolds with the license header of the project and check that they parse
+	 * Then we will write them into the project
+	 */
+	//checking the existing files
+	for _, v := range scaffolds {
+		if _, err := os.Stat(filepath.Join(p.Destination, v.Path)); err == nil && !v.Replace {
+			return nil, fmt.Errorf("%s already exists in the project", v.Path)
+		}
+	}
+
+	//rendering the scaffolds
+	templates, err := p.Template.FS()
+	if err != nil {
+		return nil, err
+	}
+	rendered := map[string][]byte{}
+	for _, v := range scaffolds {
+		b, err := fs.ReadFile(templates, ScaffoldsPath+"/"+v.Template)
+		if err != nil {
+			//error while reading the scaffold
+			fmt.Println("Error while reading the scaffold", v.Template, "from the", p.Template)
+			return nil, err
+		}
+		f := generate.NewFile(v.Path, b)
+		d := data
+		d.Endpoint = v.Endpoint
+		refactors := []generate.Refactor{
+			{Name: TemplateRender, Source: generate.NewTemplateRefactor(d)},
+			p.LicenseRefactor(),
+		}
+		for i := range refactors {
+			err = refactors[i].DoFile(context.Background(), f)
+			if err != nil {
+				return nil, &generate.Error{Source: v.Template, Refactor: refactors[i].Name, Err: err}
+			}
+		}
+		b, err = f.Bytes()
+		if err == nil {
+			_, err = parser.ParseFile(token.NewFileSet(), v.Path, b, parser.ParseComments)
+		}
+		if err != nil {
+			//error while parsing the rendered scaffold
+			fmt.Println("Error while parsing the scaffold", v.Template, "rendered for", v.Path)
+			return nil, err
+		}
+		rendered[v.Path] = b
+	}
+
+	//writing the files
+	files := []string{}
+	for _, v := range scaffolds {
+		file := filepath.Join(p.Destination, v.Path)
+		err = os.MkdirAll(filepath.Dir(file), 0755)
+		if err == nil {
+			err = ioutil.WriteFile(file, rendered[v.Path], 0644)
+		}
+		if err != nil {
+			//error while writing the file
+			fmt.Println("Error while writing the file", file)
+			return files, err
+		}
+		files = append(files, v.Path)
+	}
+	return files, nil
+}
+
+//fileName returns the name of the go file without the extension for the given name with the given kind as its suffix
+//like get_users_handler. The kind keeps the name from ending in _test or in a GOOS or GOARCH like _linux which would
+//leave the file out of the build.
+func fileName(name, kind string) string {
+	return generate.SnakeCase(name) + "_" + kind
+}
@@ -0,0 +1,260 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project_test
+
+import (
+	"fmt"
+	"go/build"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/project"
+)
+
+/*
+ * This file contains the tests written for the source code in scaffold.go
+ */
+
+var parsefieldstcs = []struct {
+	Name     string
+	Fields   string
+	Expected []project.ModelField
+	Error    bool
+}{
+	{"No fields", "", []project.ModelField{}, false},
+	{"Many fields", "name:string, created_on:Time,age:int", []project.ModelField{{"Name", "string"}, {"CreatedOn", "time"}, {"Age", "int"}}, false},
+	{"Field without a type", "name", nil, true},
+	{"Unsupported type", "tags:[]string", nil, true},
+	{"Invalid field name", "1st:string", nil, true},
+}
+
+//TestParseFields is the test suite for parsing the fields of a model
+func TestParseFields(t *testing.T) {
+	for _, v := range parsefieldstcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			f, err := project.ParseFields(v.Fields)
+			if err == nil && v.Error {
+				t.Error("Expected an error. Got none.")
+				return
+			}
+			if err != nil && !v.Error {
+				t.Error("Didn't expect an error. Got one", err.Error())
+				return
+			}
+			if !reflect.DeepEqual(f, v.Expected) {
+				t.Error("Expected", v.Expected, "Got", f)
+			}
+		})
+	}
+}
+
+var addroutetcs = []struct {
+	Name     string
+	Route    project.RouteSpec
+	Expected []string
+	Contains []string
+	Error    bool
+}{
+	{
+		"Route with the default handler",
+		project.RouteSpec{Version: "v1", Pattern: "/users", Method: "get", Register: true},
+		[]string{"routes/get_users_handler.go", "routes/get_users_handler_test.go"},
+		[]string{"func GetUsers(", "http.MethodGet", `Pattern:     "/users"`, "AddRoutes("},
+		false,
+	},
+	{
+		"Route of another version",
+		project.RouteSpec{Version: "v2", Pattern: "/users", Method: "POST", Handler: "CreateUser", Register: true},
+		[]string{"routes/create_user_handler.go", "routes/create_user_handler_test.go"},
+		[]string{"func CreateUser(", "http.MethodPost", `Version:     "v2"`},
+		false,
+	},
+	{
+		"Handler without a route",
+		project.RouteSpec{Pattern: "/list-orders", Method: "GET", Handler: "ListOrders"},
+		[]string{"routes/list_orders_handler.go", "routes/list_orders_handler_test.go"},
+		[]string{"func ListOrders("},
+		false,
+	},
+	{
+		"Pattern ending in test",
+		project.RouteSpec{Version: "v1", Pattern: "/test", Method: "GET", Register: true},
+		[]string{"routes/get_test_handler.go", "routes/get_test_handler_test.go"},
+		[]string{"func GetTest(", `Pattern:     "/test"`},
+		false,
+	},
+	{
+		"Pattern ending in an os and an arch",
+		project.RouteSpec{Version: "v1", Pattern: "/users/linux/amd64", Method: "GET", Register: true},
+		[]string{"routes/get_users_linux_amd64_handler.go", "routes/get_users_linux_amd64_handler_test.go"},
+		[]string{"func GetUsersLinuxAmd64("},
+		false,
+	},
+	{
+		"Handler named after an os",
+		project.RouteSpec{Pattern: "/windows", Method: "GET", Handler: "Windows"},
+		[]string{"routes/windows_handler.go", "routes/windows_handler_test.go"},
+		[]string{"func Windows("},
+		false,
+	},
+	{"Existing route", project.RouteSpec{Version: "v1", Pattern: "/users", Method: "POST", Register: true}, nil, nil, true},
+	{"Existing handler", project.RouteSpec{Version: "v1", Pattern: "/people", Method: "GET", Handler: "GetUsers", Register: true}, nil, nil, true},
+	{"Unsupported method", project.RouteSpec{Pattern: "/users", Method: "TRACE"}, nil, nil, true},
+	{"Invalid pattern", project.RouteSpec{Pattern: "users"}, nil, nil, true},
+	{"Unexported handler", project.RouteSpec{Pattern: "/users", Handler: "users"}, nil, nil, true},
+}
+
+var addmodeltcs = []struct {
+	Name       string
+	Components *project.Components
+	Model      project.ModelSpec
+	Pattern    string
+	Expected   []string
+	Contains   []string
+	Error      bool
+}{
+	{
+		"Model with the default pattern",
+		nil,
+		project.ModelSpec{Name: "order_item", Fields: []project.ModelField{{"Quantity", "int"}, {"ShippedOn", "time"}}},
+		"",
+		[]string{"models/order_item_model.go", "routes/order_items_handler.go", "routes/order_items_handler_test.go"},
+		[]string{"type OrderItem struct", "ShippedOn time.Time `json:\"shippedOn\"`", "func OrderItems(", `Pattern:     "/order-items"`, "appCtx.Db"},
+		false,
+	},
+	{
+		"Model with a pattern",
+		&project.Components{Database: true},
+		project.ModelSpec{Name: "Category"},
+		"/categories/all",
+		[]string{"models/category_model.go", "routes/categories_handler.go", "routes/categories_handler_test.go"},
+		[]string{"type Category struct", "func Categories(", `Pattern:     "/categories/all"`},
+		false,
+	},
+	{
+		"Model named after an os",
+		&project.Components{Database: true},
+		project.ModelSpec{Name: "UserLinux"},
+		"",
+		[]string{"models/user_linux_model.go", "routes/user_linuxes_handler.go", "routes/user_linuxes_handler_test.go"},
+		[]string{"type UserLinux struct", `Pattern:     "/user-linuxes"`},
+		false,
+	},
+	{"Project without the database", &project.Components{Examples: true}, project.ModelSpec{Name: "User"}, "", nil, nil, true},
+	{"Invalid model name", nil, project.ModelSpec{Name: "1st"}, "", nil, nil, true},
+}
+
+//TestAddRoute adds the routes one after the other to a generated project
+//and checks the generated files and that the project still builds
+func TestAddRoute(t *testing.T) {
+	project.Templates = os.DirFS(templatesDir)
+	p := testProject(filepath.Join(t.TempDir(), "orders"))
+	p.Components = &project.Components{}
+	err := p.Setup()
+	if err != nil {
+		t.Fatal("Error while generating the project", err)
+	}
+	for _, v := range addroutetcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			files, err := project.AddRoute(p.Destination, v.Route)
+			checkScaffolds(t, p.Destination, files, err, v.Expected, v.Contains, v.Error)
+		})
+	}
+	checkPlaceholders(t, p.Destination)
+	if env, build := harnessEnv(t); build {
+		buildVariant(t, p, env)
+	}
+}
+
+//TestAddModel adds the models to the projects generated with the components
+//and checks the generated files and that the projects still build
+func TestAddModel(t *testing.T) {
+	project.Templates = os.DirFS(templatesDir)
+	env, build := harnessEnv(t)
+	for _, v := range addmodeltcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			p := testProject(filepath.Join(t.TempDir(), "orders"))
+			p.Components = v.Components
+			err := p.Setup()
+			if err != nil {
+				t.Fatal("Error while generating the project", err)
+			}
+			files, err := project.AddModel(p.Destination, v.Model, "v1", v.Pattern)
+			checkScaffolds(t, p.Destination, files, err, v.Expected, v.Contains, v.Error)
+			if err != nil || t.Failed() {
+				return
+			}
+			checkPlaceholders(t, p.Destination)
+			if build {
+				buildVariant(t, p, env)
+			}
+		})
+	}
+}
+
+//buildContexts are the platforms on which the files added by the generators have to be part of the build
+var buildContexts = [][2]string{{"linux", "amd64"}, {"windows", "arm64"}, {"darwin", "amd64"}, {"plan9", "386"}}
+
+//checkBuildable fails the test if any of the files added by a generator is left out of the build on any platform
+//or if a file whose name is made from a route or a model is taken as a test
+func checkBuildable(t *testing.T, dst string, files []string) {
+	for _, f := range files {
+		test := strings.HasSuffix(f, "_handler_test.go")
+		if strings.HasSuffix(f, "_test.go") != test {
+			t.Error("Expected", f, "to be a test file only if it is the test of a handler")
+		}
+		for _, c := range buildContexts {
+			ctx := build.Default
+			ctx.GOOS, ctx.GOARCH = c[0], c[1]
+			ok, err := ctx.MatchFile(filepath.Join(dst, filepath.Dir(f)), filepath.Base(f))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok {
+				t.Error("Expected", f, "to be part of the build on", c[0]+"/"+c[1])
+			}
+		}
+	}
+}
+
+//checkScaffolds checks the error and the files added by a generator along with their content
+func checkScaffolds(t *testing.T, dst string, files []string, err error, expected, contains []string, expectError bool) {
+	if err == nil && expectError {
+		t.Error("Expected an error. Got none.")
+		return
+	}
+	if err != nil && !expectError {
+		t.Error("Didn't expect an error. Got one", err.Error())
+		return
+	}
+	for i := range files {
+		files[i] = filepath.ToSlash(files[i])
+	}
+	if !reflect.DeepEqual(files, expected) {
+		t.Error("Expected the files", expected, "Got", files)
+		return
+	}
+	checkBuildable(t, dst, files)
+	content := ""
+	for _, f := range files {
+		b, err := ioutil.ReadFile(filepath.Join(dst, f))
+		if err != nil {
+			t.Fatal(err)
+		}
+		content += string(b)
+	}
+	for _, c := range contains {
+		if !strings.Contains(content, c) {
+			t.Error("Expected the generated files to contain", c)
+		}
+	}
+}
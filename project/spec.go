@@ -0,0 +1,84 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+/*
+ * This file contains the utilities for reading the project specification from a file
+ */
+
+//Load reads the project specification from the given file. The format of the file
+//is identified from its extension. Files ending with .yaml or .yml are read as yaml.
+//Files ending with .json are read as json. Fields missing in the file are left empty
+//so that they can be filled in by other means like flags or prompts.
+func Load(file string) (*Project, error) {
+	/*
+	 * We will read the file
+	 * Then decode it based on the extension of the file
+	 */
+	//reading the file
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		//error while reading the project spec file
+		fmt.Println("Error while reading the project spec file", file)
+		return nil, err
+	}
+
+	//decoding the file as per the extension
+	p := &Project{}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, p)
+	case ".json":
+		err = json.Unmarshal(b, p)
+	default:
+		return nil, fmt.Errorf("%s is not a supported project spec file. Use a .yaml, .yml or .json file", file)
+	}
+	if err != nil {
+		//error while decoding the project spec file
+		fmt.Println("Error while decoding the project spec file", file)
+		return nil, err
+	}
+	return p, nil
+}
+
+//Merge fills the empty fields of the project with the values in the given project.
+//Fields that already have a value are left untouched.
+func (p *Project) Merge(o Project) {
+	setIfEmpty(&p.Name, o.Name)
+	setIfEmpty(&p.Description, o.Description)
+	setIfEmpty(&p.Author.Name, o.Author.Name)
+	setIfEmpty(&p.Author.Email, o.Author.Email)
+	setIfEmpty(&p.Destination, o.Destination)
+	setIfEmpty(&p.Package, o.Package)
+	if len(p.License.Type) == 0 {
+		p.License.Type = o.License.Type
+	}
+	setIfEmpty(&p.License.Year, o.License.Year)
+	setIfEmpty(&p.License.Organisation, o.License.Organisation)
+	setIfEmpty(&p.LicensesDir, o.LicensesDir)
+	setIfEmpty(&p.OpenAPI, o.OpenAPI)
+	if p.Template.IsBuiltIn() {
+		p.Template = o.Template
+	}
+	if p.Components == nil {
+		p.Components = o.Components
+	}
+}
+
+func setIfEmpty(dst *string, src string) {
+	if len(*dst) == 0 {
+		*dst = src
+	}
+}
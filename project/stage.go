@@ -0,0 +1,143 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+/*
+ * This file contains the utilities to move the project generated in the staging directory into the destination
+ */
+
+//commit moves the given files rendered in the staging directory into the destination. The files are relative
+//to the staging directory. If the destination doesn't exist, the staging directory itself is renamed to the destination.
+//Else the files are moved one by one and the existing files they replace are backed up. If moving any of the files fails,
+//the destination is restored to how it was before the commit.
+func commit(staging, dst string, files []string) error {
+	/*
+	 * If the destination doesn't exist we will rename the staging directory
+	 * Else we will create the backup directory
+	 * Then we will move the files into the destination
+	 * If anything fails we will rollback
+	 */
+	//renaming the staging directory if the destination doesn't exist
+	_, err := os.Stat(dst)
+	if os.IsNotExist(err) {
+		err = os.Chmod(staging, 0755)
+		if err != nil {
+			return err
+		}
+		return os.Rename(staging, dst)
+	}
+	if err != nil {
+		//error while checking the destination
+		fmt.Println("Error while reading the info of the destination", dst)
+		return err
+	}
+
+	//creating the backup directory
+	backup, err := ioutil.TempDir(filepath.Dir(dst), "."+filepath.Base(dst)+"-backup-")
+	if err != nil {
+		//error while creating the backup directory
+		fmt.Println("Error while creating the backup directory for", dst)
+		return err
+	}
+	defer os.RemoveAll(backup)
+
+	//moving the files
+	t := &transaction{backups: map[string]string{}}
+	for _, v := range files {
+		err = t.move(staging+Separator+v, dst+Separator+v, backup+Separator+v)
+		if err != nil {
+			//error while moving the file into the destination
+			fmt.Println("Error while moving", v, "into the destination. Restoring the destination", dst)
+			t.rollback()
+			return err
+		}
+	}
+	return nil
+}
+
+//transaction keeps track of the changes made in the destination while moving the files into it
+//so that they can be rolled back
+type transaction struct {
+	//dirs are the directories created in the destination
+	dirs []string
+	//moved are the files moved into the destination
+	moved []string
+	//backups has the backup of the files replaced in the destination
+	backups map[string]string
+}
+
+//move will move the src file to the dst. If the dst exists it is moved to the backup.
+func (t *transaction) move(src, dst, backup string) error {
+	/*
+	 * We will create the missing directories of the destination
+	 * We will backup the existing file
+	 * Then we will move the file
+	 */
+	//creating the missing directories
+	missing := []string{}
+	for d := filepath.Dir(dst); ; d = filepath.Dir(d) {
+		_, err := os.Stat(d)
+		if err == nil || d == filepath.Dir(d) {
+			break
+		}
+		missing = append([]string{d}, missing...)
+	}
+	for _, d := range missing {
+		err := os.Mkdir(d, 0775)
+		if err != nil {
+			return err
+		}
+		t.dirs = append(t.dirs, d)
+	}
+
+	//backing up the existing file
+	if _, err := os.Stat(dst); err == nil {
+		err = os.MkdirAll(filepath.Dir(backup), 0775)
+		if err != nil {
+			return err
+		}
+		err = os.Rename(dst, backup)
+		if err != nil {
+			return err
+		}
+		t.backups[dst] = backup
+	}
+
+	//moving the file
+	err := os.Rename(src, dst)
+	if err != nil {
+		return err
+	}
+	t.moved = append(t.moved, dst)
+	return nil
+}
+
+//rollback will undo the changes made in the destination
+func (t *transaction) rollback() {
+	/*
+	 * We will remove the moved files
+	 * We will restore the backups
+	 * Then remove the created directories
+	 */
+	for i := len(t.moved) - 1; i >= 0; i-- {
+		os.Remove(t.moved[i])
+	}
+	for dst, backup := range t.backups {
+		err := os.Rename(backup, dst)
+		if err != nil {
+			fmt.Println("Error while restoring", dst, "from the backup", backup, err)
+		}
+	}
+	for i := len(t.dirs) - 1; i >= 0; i-- {
+		os.Remove(t.dirs[i])
+	}
+}
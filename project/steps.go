@@ -0,0 +1,162 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+/*
+ * This file contains the post generation steps run in the generated project
+ */
+
+const (
+	//ModInitStep initializes go modules in the project if it doesn't have a go.mod
+	ModInitStep = "mod-init"
+	//ModTidyStep runs go mod tidy in the project
+	ModTidyStep = "mod-tidy"
+	//GitStep initializes a git repository in the project and makes the first commit
+	GitStep = "git"
+	//BuildStep builds the project
+	BuildStep = "build"
+	//TestStep runs the tests of the project
+	TestStep = "test"
+)
+
+//Steps are the post generation steps in the order in which they are run
+var Steps = []string{ModInitStep, ModTidyStep, GitStep, BuildStep, TestStep}
+
+//stepDependencies has the step each post generation step depends on. A step is skipped if the step it depends on fails.
+//The git step doesn't depend on any step.
+var stepDependencies = map[string]string{
+	ModTidyStep: ModInitStep,
+	BuildStep:   ModTidyStep,
+	TestStep:    BuildStep,
+}
+
+//StepStatus is the status of a post generation step
+type StepStatus string
+
+const (
+	//StepDone is a step that ran successfully
+	StepDone StepStatus = "done"
+	//StepSkipped is a step that was skipped
+	StepSkipped StepStatus = "skipped"
+	//StepFailed is a step that failed
+	StepFailed StepStatus = "failed"
+)
+
+//StepResult is the result of a post generation step
+type StepResult struct {
+	//Name of the step
+	Name string
+	//Status of the step
+	Status StepStatus
+	//Detail tells why the step was skipped or failed
+	Detail string
+}
+
+//PostSteps configures the post generation steps
+type PostSteps struct {
+	//Skip has the names of the steps to be skipped
+	Skip map[string]bool
+	//GoFlags is set as the GOFLAGS of the go commands run by the steps. For example -mod=mod or -mod=vendor
+	GoFlags string
+	//Output is where the output of the commands run by the steps is written. Defaults to the standard error
+	Output io.Writer
+}
+
+//RunPostSteps runs the post generation steps in the generated project and returns their results.
+//Once a step fails, the steps depending on it directly or through another step are skipped.
+func (p Project) RunPostSteps(s PostSteps) []StepResult {
+	/*
+	 * We will iterate through the steps
+	 * If the step is asked to be skipped or the step it depends on failed, we will skip it
+	 * Else we will run the step
+	 */
+	if s.Output == nil {
+		s.Output = os.Stderr
+	}
+	results := []StepResult{}
+	//failed has the steps that failed or were skipped because of a failure along with the step that failed
+	failed := map[string]string{}
+	for _, v := range Steps {
+		if s.Skip[v] {
+			results = append(results, StepResult{Name: v, Status: StepSkipped, Detail: "skipped by the user"})
+			continue
+		}
+		if f, ok := failed[stepDependencies[v]]; ok {
+			failed[v] = f
+			results = append(results, StepResult{Name: v, Status: StepSkipped, Detail: f + " failed"})
+			continue
+		}
+		fmt.Fprintln(s.Output, "Running", v, "in", p.Destination)
+		r := p.runStep(v, s)
+		if r.Status == StepFailed {
+			failed[v] = v
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+//runStep runs the post generation step with the given name
+func (p Project) runStep(name string, s PostSteps) StepResult {
+	/*
+	 * We will check whether the step is required
+	 * Then we will run the commands of the step
+	 */
+	//checking whether the step is required
+	r := StepResult{Name: name, Status: StepDone}
+	switch name {
+	case ModInitStep:
+		if _, err := os.Stat(p.Destination + Separator + "go.mod"); err == nil {
+			r.Status, r.Detail = StepSkipped, "go.mod already exists"
+			return r
+		}
+	case GitStep:
+		if _, err := p.command(s, "git", "rev-parse", "--is-inside-work-tree").Output(); err == nil {
+			r.Status, r.Detail = StepSkipped, "already in a git repository"
+			return r
+		}
+	}
+
+	//running the commands of the step
+	commands := map[string][][]string{
+		ModInitStep: {{"go", "mod", "init", p.Package}},
+		ModTidyStep: {{"go", "mod", "tidy"}},
+		GitStep: {
+			{"git", "init", "--quiet"},
+			{"git", "add", "-A"},
+			{"git", "commit", "--quiet", "-m", "Initial commit generated by web-starter"},
+		},
+		BuildStep: {{"go", "build", "./..."}},
+		TestStep:  {{"go", "test", "./..."}},
+	}
+	for _, v := range commands[name] {
+		c := p.command(s, v[0], v[1:]...)
+		c.Stdout, c.Stderr = s.Output, s.Output
+		err := c.Run()
+		if err != nil {
+			r.Status, r.Detail = StepFailed, strings.Join(v, " ")+": "+err.Error()
+			return r
+		}
+	}
+	return r
+}
+
+//command returns the command to be run in the project destination
+func (p Project) command(s PostSteps, name string, args ...string) *exec.Cmd {
+	c := exec.Command(name, args...)
+	c.Dir = p.Destination
+	if name == "go" && len(s.GoFlags) > 0 {
+		c.Env = append(os.Environ(), "GOFLAGS="+s.GoFlags)
+	}
+	return c
+}
@@ -0,0 +1,93 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/project"
+)
+
+/*
+ * This file contains the tests written for the source code in steps.go
+ */
+
+var poststepstcs = []struct {
+	Name     string
+	Setup    func(t *testing.T, dir string)
+	Skip     []string
+	Expected []project.StepStatus
+}{
+	{
+		"All the steps run in a new project",
+		func(t *testing.T, dir string) {},
+		nil,
+		[]project.StepStatus{project.StepDone, project.StepDone, project.StepDone, project.StepDone, project.StepDone},
+	},
+	{
+		"Modules are not initialized again",
+		func(t *testing.T, dir string) {
+			writeFile(filepath.Join(dir, "go.mod"), "module example.com/orders\n")
+		},
+		[]string{project.GitStep},
+		[]project.StepStatus{project.StepSkipped, project.StepDone, project.StepSkipped, project.StepDone, project.StepDone},
+	},
+	{
+		"Steps after a failing step are skipped",
+		func(t *testing.T, dir string) {
+			writeFile(filepath.Join(dir, "broken.go"), "package main\n\nfunc broken() { undefined() }\n")
+		},
+		[]string{project.GitStep},
+		[]project.StepStatus{project.StepDone, project.StepDone, project.StepSkipped, project.StepFailed, project.StepSkipped},
+	},
+	{
+		"Project is built and tested even if git fails",
+		func(t *testing.T, dir string) {
+			config := filepath.Join(t.TempDir(), "gitconfig")
+			writeFile(config, "[broken")
+			t.Setenv("GIT_CONFIG_GLOBAL", config)
+		},
+		nil,
+		[]project.StepStatus{project.StepDone, project.StepDone, project.StepFailed, project.StepDone, project.StepDone},
+	},
+}
+
+//TestRunPostSteps runs the post generation steps on a stdlib only project
+func TestRunPostSteps(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not available")
+	}
+	skipWithoutGit(t)
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	for _, v := range poststepstcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			dir := t.TempDir()
+			writeFile(filepath.Join(dir, "main.go"), "package main\n\nfunc main() {}\n")
+			v.Setup(t, dir)
+			p := project.Project{Destination: dir, Package: "example.com/orders"}
+			skip := map[string]bool{}
+			for _, s := range v.Skip {
+				skip[s] = true
+			}
+			results := p.RunPostSteps(project.PostSteps{Skip: skip, GoFlags: "-mod=mod", Output: ioutil.Discard})
+			if len(results) != len(v.Expected) {
+				t.Fatal("Expected", len(v.Expected), "results. Got", results)
+			}
+			for i, r := range results {
+				if r.Status != v.Expected[i] {
+					t.Errorf("Expected the step %s to be %s. Got %s %s", r.Name, v.Expected[i], r.Status, r.Detail)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+/*
+ * This file contains the definitions of the template from which a project is generated.
+ * Apart from the built in template, a template can be a local directory or a git repository.
+ */
+
+//Template is the template from which the project is generated
+type Template struct {
+	//Source is the local directory or the git url of the template. Empty means the built in template
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+	//Ref is the git ref of the template to be used. Empty means the default branch of the repository
+	Ref string `json:"ref,omitempty" yaml:"ref,omitempty"`
+	//Commit is the git commit of the template used for generating the project. If given, it is used instead of the ref
+	Commit string `json:"commit,omitempty" yaml:"commit,omitempty"`
+	//Dir is the local directory having the resolved template
+	Dir string `json:"-" yaml:"-"`
+}
+
+//ParseTemplate parses the given template argument. The argument is a local directory or
+//a git url with an optional @ref suffix like https://github.com/acme/worker-template@v1.0.0
+func ParseTemplate(arg string) Template {
+	/*
+	 * If the argument is an existing directory, it is a local template
+	 * Else we will check for the ref suffix of the git url
+	 */
+	if s, err := os.Stat(arg); err == nil && s.IsDir() {
+		return Template{Source: arg}
+	}
+	i := strings.LastIndex(arg, "@")
+	if i < 0 {
+		return Template{Source: arg}
+	}
+	url, ref := arg[:i], arg[i+1:]
+	//scp like urls (git@github.com:acme/template) and user info in the urls (ssh://git@github.com/acme/template)
+	//have an @ which is not followed by a ref
+	if j := strings.Index(url, "://"); j >= 0 {
+		url = url[j+3:]
+	}
+	if len(ref) == 0 || strings.Contains(ref, ":") || !strings.Contains(url, "/") {
+		return Template{Source: arg}
+	}
+	return Template{Source: arg[:i], Ref: ref}
+}
+
+//String is the stringer implementation of the template
+func (t Template) String() string {
+	if len(t.Source) == 0 {
+		return "built in template"
+	}
+	if len(t.Ref) == 0 {
+		return t.Source
+	}
+	return t.Source + "@" + t.Ref
+}
+
+//IsBuiltIn tells whether the template is the built in template
+func (t Template) IsBuiltIn() bool {
+	return len(t.Source) == 0
+}
+
+//Resolve makes the template available in a local directory. Local directories are used as it is
+//unless a ref or commit is asked for, in which case they are cloned like any other git repository.
+//Git repositories are cloned into the template cache and the commit, if given, else the ref is checked out.
+//If the repository is already cached, it is fetched again. Fetch failures are ignored so that the cached
+//templates work offline.
+func (t *Template) Resolve() error {
+	/*
+	 * Built in and already resolved templates needn't be resolved
+	 * If the source is a local directory without a ref or commit we will use it as it is
+	 * Else we will clone or fetch the repository into the cache
+	 * Then we will checkout the commit or the ref
+	 */
+	if t.IsBuiltIn() || len(t.Dir) > 0 {
+		return nil
+	}
+
+	//using the local directory
+	if s, err := os.Stat(t.Source); err == nil && s.IsDir() && len(t.Ref) == 0 && len(t.Commit) == 0 {
+		t.Dir, err = filepath.Abs(t.Source)
+		if err != nil {
+			return err
+		}
+		t.Source = t.Dir
+		//recording the commit if the directory is the top level of a git repository. It is fine if it is not one.
+		//The commit of a repository enclosing the directory is not recorded since its clone doesn't have the template at its root
+		if isRepoRoot(t.Dir) {
+			t.Commit, _ = git(t.Dir, "rev-parse", "HEAD")
+		}
+		return nil
+	}
+
+	//cloning or fetching the repository
+	if s, err := os.Stat(t.Source); err == nil && s.IsDir() {
+		t.Source, err = filepath.Abs(t.Source)
+		if err != nil {
+			return err
+		}
+	}
+	dir, err := TemplateCacheDir()
+	if err != nil {
+		return err
+	}
+	sum := sha1.Sum([]byte(t.Source))
+	dir = filepath.Join(dir, hex.EncodeToString(sum[:]))
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		fmt.Println("Fetching the template", t.Source)
+		_, err = git("", "clone", "--quiet", "--no-checkout", "--", t.Source, dir)
+		if err != nil {
+			//error while cloning the template
+			fmt.Println("Error while cloning the template", t.Source)
+			return err
+		}
+	} else if _, err = git(dir, "fetch", "--quiet", "--tags", "--force", "origin"); err != nil {
+		fmt.Println("Couldn't fetch the template", t.Source, "Using the cached one.", err)
+	}
+
+	//checking out the commit or the ref
+	commit, err := t.commit(dir)
+	if err != nil {
+		//error while resolving the ref of the template
+		fmt.Println("Error while resolving the ref", t.Ref, "of the template", t.Source)
+		return err
+	}
+	_, err = git(dir, "checkout", "--quiet", "--force", "--detach", commit)
+	if err != nil {
+		//error while checking out the template
+		fmt.Println("Error while checking out the commit", commit, "of the template", t.Source)
+		return err
+	}
+	t.Dir, t.Commit = dir, commit
+	return nil
+}
+
+//commit resolves the commit or the ref of the template to a commit in the given repository.
+//Branches are resolved from the remote so that the cached local branches are not used.
+func (t Template) commit(dir string) (string, error) {
+	if len(t.Commit) > 0 {
+		return git(dir, "rev-parse", "--verify", "--quiet", t.Commit+"^{commit}")
+	}
+	if len(t.Ref) == 0 {
+		return git(dir, "rev-parse", "--verify", "--quiet", "origin/HEAD^{commit}")
+	}
+	if c, err := git(dir, "rev-parse", "--verify", "--quiet", "origin/"+t.Ref+"^{commit}"); err == nil {
+		return c, nil
+	}
+	return git(dir, "rev-parse", "--verify", "--quiet", t.Ref+"^{commit}")
+}
+
+//isRepoRoot tells whether the given directory is the top level directory of a git repository
+func isRepoRoot(dir string) bool {
+	top, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return false
+	}
+	top, err = filepath.EvalSymlinks(top)
+	if err != nil {
+		return false
+	}
+	dir, err = filepath.EvalSymlinks(dir)
+	return err == nil && filepath.Clean(top) == filepath.Clean(dir)
+}
+
+//FS returns the file system of the template. The template has to be resolved before.
+func (t Template) FS() (fs.FS, error) {
+	if t.IsBuiltIn() {
+		return Templates, nil
+	}
+	if len(t.Dir) == 0 {
+		return nil, fmt.Errorf("the template %s is not resolved", t)
+	}
+	return os.DirFS(t.Dir), nil
+}
+
+//TemplateCacheDir returns the directory in which the templates fetched from git are cached
+func TemplateCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "web-starter", "templates"), nil
+}
+
+//git runs the git command with the given arguments in the given directory and returns the trimmed output
+func git(dir string, args ...string) (string, error) {
+	c := exec.Command("git", args...)
+	c.Dir = dir
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	c.Stdout, c.Stderr = out, errOut
+	err := c.Run()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v %s", strings.Join(args, " "), err, strings.TrimSpace(errOut.String()))
+	}
+	return strings.TrimSpace(out.String()), nil
+}
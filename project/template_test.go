@@ -0,0 +1,155 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project_test
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/project"
+)
+
+/*
+ * This file contains the tests written for the source code in template.go
+ */
+
+var parsetemplatetcs = []struct {
+	Name     string
+	Arg      string
+	Expected project.Template
+}{
+	{"Https url without ref", "https://github.com/acme/worker", project.Template{Source: "https://github.com/acme/worker"}},
+	{"Https url with ref", "https://github.com/acme/worker@v1.0.0", project.Template{Source: "https://github.com/acme/worker", Ref: "v1.0.0"}},
+	{"Branch with slash", "https://github.com/acme/worker@feature/grpc", project.Template{Source: "https://github.com/acme/worker", Ref: "feature/grpc"}},
+	{"Scp like url", "git@github.com:acme/worker.git", project.Template{Source: "git@github.com:acme/worker.git"}},
+	{"Scp like url with ref", "git@github.com:acme/worker.git@main", project.Template{Source: "git@github.com:acme/worker.git", Ref: "main"}},
+	{"Ssh url with user", "ssh://git@github.com/acme/worker", project.Template{Source: "ssh://git@github.com/acme/worker"}},
+	{"File url with ref", "file:///srv/templates/worker@v2", project.Template{Source: "file:///srv/templates/worker", Ref: "v2"}},
+}
+
+//TestParseTemplate is the test suite for parsing the template argument
+func TestParseTemplate(t *testing.T) {
+	for _, v := range parsetemplatetcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			if got := project.ParseTemplate(v.Arg); got != v.Expected {
+				t.Errorf("Expected %+v. Got %+v", v.Expected, got)
+			}
+		})
+	}
+}
+
+//TestTemplateResolveGit resolves a template from a file:// git remote
+func TestTemplateResolveGit(t *testing.T) {
+	/*
+	 * We will create a git repository with two commits and tag the first one
+	 * Then we will resolve the tag from the file:// remote
+	 * Then check the content of the template is from the tagged commit
+	 */
+	skipWithoutGit(t)
+	repo := t.TempDir()
+	runGit(t, repo, "init", "--quiet")
+	writeFile(filepath.Join(repo, "manifest.yaml"), "files:\n  - path: README.md\n")
+	writeFile(filepath.Join(repo, "README.md"), "v1")
+	runGit(t, repo, "add", "-A")
+	runGit(t, repo, "commit", "--quiet", "-m", "v1")
+	runGit(t, repo, "tag", "v1")
+	writeFile(filepath.Join(repo, "README.md"), "v2")
+	runGit(t, repo, "commit", "--quiet", "-am", "v2")
+
+	for _, v := range []struct {
+		Arg      string
+		Expected string
+	}{{"file://" + repo + "@v1", "v1"}, {"file://" + repo, "v2"}} {
+		tmpl := project.ParseTemplate(v.Arg)
+		err := tmpl.Resolve()
+		if err != nil {
+			t.Fatal("Error while resolving the template", v.Arg, err)
+		}
+		if len(tmpl.Commit) == 0 {
+			t.Error("Expected the commit of the template to be recorded for", v.Arg)
+		}
+		fsys, err := tmpl.FS()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := fs.ReadFile(fsys, "README.md")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != v.Expected {
+			t.Error("Expected the template", v.Arg, "to have", v.Expected, "Got", string(b))
+		}
+	}
+}
+
+//TestTemplateResolveLocal resolves the local directories as templates and checks that the commit is recorded
+//only for the top level of a git repository
+func TestTemplateResolveLocal(t *testing.T) {
+	skipWithoutGit(t)
+	repo := t.TempDir()
+	runGit(t, repo, "init", "--quiet")
+	sub := filepath.Join(repo, "templates", "worker")
+	err := os.MkdirAll(sub, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(filepath.Join(sub, "manifest.yaml"), "files:\n  - path: README.md\n")
+	runGit(t, repo, "add", "-A")
+	runGit(t, repo, "commit", "--quiet", "-m", "templates")
+
+	for _, v := range []struct {
+		Name   string
+		Dir    string
+		Commit bool
+	}{{"Top level of the repository", repo, true}, {"Directory in the repository", sub, false}, {"Directory outside a repository", t.TempDir(), false}} {
+		fmt.Println("Testing", v.Name)
+		tmpl := project.ParseTemplate(v.Dir)
+		err := tmpl.Resolve()
+		if err != nil {
+			t.Fatal("Error while resolving the template", v.Dir, err)
+		}
+		if (len(tmpl.Commit) > 0) != v.Commit {
+			t.Error("Expected the commit to be recorded", v.Commit, "for", v.Name, "Got", tmpl.Commit)
+		}
+	}
+}
+
+//TestTemplateResolveOption checks that a template source starting with - is not taken as an option of git
+func TestTemplateResolveOption(t *testing.T) {
+	skipWithoutGit(t)
+	marker := filepath.Join(t.TempDir(), "marker")
+	tmpl := project.Template{Source: "--upload-pack=touch " + marker}
+	err := tmpl.Resolve()
+	if err == nil || !strings.Contains(err.Error(), "repository '"+tmpl.Source+"'") {
+		t.Error("Expected the source to be cloned as a repository. Got", err)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Error("Expected the source not to be run as an option of git")
+	}
+}
+
+//skipWithoutGit skips the test if git is not available. It also points the template cache to a temporary directory
+func skipWithoutGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+}
+
+//runGit runs the git command with the given arguments in the given directory
+func runGit(t *testing.T, dir string, args ...string) {
+	c := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	c.Dir = dir
+	if out, err := c.CombinedOutput(); err != nil {
+		t.Fatal("Error while running git", args, err, string(out))
+	}
+}
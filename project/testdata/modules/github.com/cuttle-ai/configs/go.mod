@@ -0,0 +1,3 @@
+module github.com/cuttle-ai/configs
+
+go 1.16
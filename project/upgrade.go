@@ -0,0 +1,530 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cuttle-ai/web-starter/generate"
+	"github.com/cuttle-ai/web-starter/version"
+)
+
+/*
+ * This file contains the utilities to upgrade a generated project to a newer version of its template.
+ * The old and new versions of the template are rendered with the answers recorded in the project and
+ * the changes between them are three way merged into the files of the project.
+ */
+
+//Repository is the git repository of web-starter. The older versions of the built in template are fetched from it
+const Repository = "https://github.com/cuttle-ai/web-starter"
+
+//UpgradeStatus tells what the upgrade did with a file of the project
+type UpgradeStatus string
+
+const (
+	//FileAdded is a file that is new in the template and was added to the project
+	FileAdded UpgradeStatus = "added"
+	//FileUpdated is a file that wasn't modified in the project and was replaced with the one in the new template
+	FileUpdated UpgradeStatus = "updated"
+	//FileMerged is a file whose changes in the project and in the template were merged without conflicts
+	FileMerged UpgradeStatus = "merged"
+	//FileConflicted is a file whose changes in the project and in the template conflict.
+	//The conflicts are marked in the file
+	FileConflicted UpgradeStatus = "conflict"
+	//FileUnchanged is a file that the template didn't change
+	FileUnchanged UpgradeStatus = "unchanged"
+	//FileDeletedLocally is a file changed in the template that was deleted from the project. It is left deleted
+	FileDeletedLocally UpgradeStatus = "deleted locally"
+	//FileRemovedFromTemplate is a file of the project that isn't part of the template anymore. It is left as it is
+	FileRemovedFromTemplate UpgradeStatus = "removed from template"
+	//FileRefactored is a file of the project that isn't part of the template whose imports, license header
+	//or module path were changed to the new package or license of the project
+	FileRefactored UpgradeStatus = "refactored"
+)
+
+//UpgradedFile is a file of the project looked at by the upgrade
+type UpgradedFile struct {
+	//Path of the file relative to the project
+	Path string
+	//Status tells what the upgrade did with the file
+	Status UpgradeStatus
+	//Conflicts is the number of conflicts marked in the file
+	Conflicts int
+}
+
+//UpgradeReport is the summary of the upgrade of a project
+type UpgradeReport struct {
+	//From is the template version from which the project was generated
+	From string
+	//To is the template version to which the project was upgraded
+	To string
+	//Files are the files of the project looked at by the upgrade
+	Files []UpgradedFile
+}
+
+//Conflicts returns the files having conflicts after the upgrade
+func (u UpgradeReport) Conflicts() []UpgradedFile {
+	files := []UpgradedFile{}
+	for _, v := range u.Files {
+		if v.Status == FileConflicted {
+			files = append(files, v)
+		}
+	}
+	return files
+}
+
+//Upgrade upgrades the project generated in the given directory to the given template. If the template is nil,
+//the latest version of the template from which the project was generated is used. The old version of the template
+//is rendered with the answers recorded in the project along with the new one. Then the changes between them are
+//merged into the files of the project. Conflicting changes are marked in the files like git does.
+func Upgrade(dir string, to *Template) (*UpgradeReport, error) {
+	/*
+	 * We will read the metadata of the project
+	 * Then we will update the project to the latest version of the template
+	 */
+	m, err := ReadMetadata(dir)
+	if err != nil {
+		return nil, err
+	}
+	latest := m.Project
+	latest.Template = Template{Source: m.Project.Template.Source, Ref: m.Project.Template.Ref}
+	if to != nil {
+		latest.Template = *to
+	}
+	return m.update(latest)
+}
+
+//Regenerate generates the project in the given directory again with the answers recorded in it. The non empty
+//fields of the given project override the recorded answers. The same version of the template from which the project
+//was generated is rendered with the old and new answers. Then the changes between them are merged into the files of the
+//project. The imports and license headers of the other go files of the project, like the ones added by the generators,
+//and the module path in the go.mod are changed to the new package and license too.
+//So a rename of the project or a switch of the license is done across the tree without losing the changes in it.
+func Regenerate(dir string, overrides Project) (*UpgradeReport, error) {
+	/*
+	 * We will read the metadata of the project
+	 * We will override the answers and validate them
+	 * Then we will update the project with the new answers
+	 */
+	m, err := ReadMetadata(dir)
+	if err != nil {
+		return nil, err
+	}
+	if m.Project.Template.IsBuiltIn() && m.Version != version.Default.Version {
+		return nil, fmt.Errorf("the project was generated by web-starter %s. Run web-starter upgrade before regenerating it with %s",
+			m.Version, version.Default.Version)
+	}
+	latest, err := m.base()
+	if err != nil {
+		return nil, err
+	}
+	overrides.Template = latest.Template
+	overrides.Merge(latest)
+	err = overrides.Template.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	err = overrides.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return m.update(overrides)
+}
+
+//update updates the project having the metadata to the given project. The version of the template from which
+//the project was generated is rendered with the recorded answers along with the given project. Then the changes
+//between them are merged into the files of the project.
+func (m Metadata) update(latest Project) (*UpgradeReport, error) {
+	/*
+	 * We will get the version of the template from which the project was generated
+	 * We will create the temporary directory for rendering the templates
+	 * We will render the old version of the template
+	 * Then we will render the new version of the template
+	 * Then we will merge the changes into the project
+	 */
+	//getting the version of the template from which the project was generated
+	old, err := m.base()
+	if err != nil {
+		return nil, err
+	}
+	dir := m.Project.Destination
+	latest.Destination = dir
+
+	//creating the temporary directory
+	tmp, err := ioutil.TempDir("", "web-starter-upgrade")
+	if err != nil {
+		//error while creating the temporary directory
+		fmt.Println("Error while creating the temporary directory for updating the project", m.Project.Name)
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	//rendering the old version of the template. It is rendered completely before resolving the new version
+	//since both of them could be checked out in the same template cache
+	_, err = old.renderAll(tmp + Separator + "base")
+	if err != nil {
+		//error while rendering the old version of the template
+		fmt.Println("Error while rendering the version of the template from which the project was generated", old.Template)
+		return nil, err
+	}
+
+	//rendering the new version of the template
+	files, err := latest.renderAll(tmp + Separator + "new")
+	if err != nil {
+		//error while rendering the new version of the template
+		fmt.Println("Error while rendering the new version of the template", latest.Template)
+		return nil, err
+	}
+
+	//merging the changes into the project
+	report := &UpgradeReport{From: m.label(), To: Metadata{Version: version.Default.Version, Project: latest}.label()}
+	err = report.merge(tmp, dir, files, old, latest)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+//base returns the project with the version of the template from which the project was generated
+func (m Metadata) base() (Project, error) {
+	/*
+	 * Templates from git are pinned to the commit recorded
+	 * The built in template is taken from the web-starter repository at the version recorded
+	 * unless it is the current version which is embedded
+	 */
+	p := m.Project
+	t := m.Project.Template
+	switch {
+	case len(t.Commit) > 0:
+		p.Template = Template{Source: t.Source, Ref: t.Ref, Commit: t.Commit}
+	case t.IsBuiltIn() && m.Version != version.Default.Version:
+		p.Template = Template{Source: Repository, Ref: m.Version}
+	case t.IsBuiltIn():
+		p.Template = Template{}
+	default:
+		return p, fmt.Errorf("the template %s from which the project was generated isn't a git repository. "+
+			"So its version used for generating the project can't be found to merge the changes", t)
+	}
+	return p, nil
+}
+
+//label returns the template version recorded in the metadata for showing to the user
+func (m Metadata) label() string {
+	t := m.Project.Template
+	if t.IsBuiltIn() {
+		return "web-starter " + m.Version
+	}
+	if len(t.Commit) > 12 {
+		return t.String() + " (" + t.Commit[:12] + ")"
+	}
+	return t.String()
+}
+
+//renderAll renders all the sources of the project into the given directory irrespective of the files existing
+//in the destination. The template of the project is resolved if required.
+//It returns the rendered files relative to the directory.
+func (p *Project) renderAll(dir string) ([]string, error) {
+	p.OnConflict, p.Keep = Overwrite, nil
+	err := p.InitSources()
+	if err != nil {
+		return nil, err
+	}
+	err = os.MkdirAll(dir, 0775)
+	if err != nil {
+		return nil, err
+	}
+	return p.render(dir)
+}
+
+//merge merges the changes between the old and new renders of the template in the given temporary directory
+//into the project in the given directory. The files are the ones in the new render. The rest of the files of the project
+//are refactored from the old project to the latest one.
+func (u *UpgradeReport) merge(tmp, dir string, files []string, old, latest Project) error {
+	/*
+	 * We will create the staging directory
+	 * We will merge each file of the new render into the staging directory
+	 * We will refactor the files which aren't part of the template into the staging directory
+	 * We will list the files which were removed from the template
+	 * Then we will move the changed files into the project
+	 */
+	//creating the staging directory
+	staging, err := ioutil.TempDir(filepath.Dir(dir), "."+filepath.Base(dir)+"-web-starter-")
+	if err != nil {
+		//error while creating the staging directory
+		fmt.Println("Error while creating the staging directory for upgrading", dir)
+		return err
+	}
+	defer os.RemoveAll(staging)
+
+	//merging the files
+	changed := []string{}
+	inNew := map[string]bool{}
+	for _, v := range files {
+		inNew[v] = true
+		f, content, err := mergeFile(v, tmp+Separator+"base"+Separator+v, dir+Separator+v, tmp+Separator+"new"+Separator+v)
+		if err != nil {
+			//error while merging the file
+			fmt.Println("Error while merging the changes of the template into", v)
+			return err
+		}
+		u.Files = append(u.Files, *f)
+		if content == nil {
+			continue
+		}
+		err = os.MkdirAll(filepath.Dir(staging+Separator+v), 0775)
+		if err != nil {
+			return err
+		}
+		mode := os.FileMode(0644)
+		if info, err := os.Stat(tmp + Separator + "new" + Separator + v); err == nil {
+			//the file keeps the permission with which the template renders it like the executable bit
+			mode = info.Mode().Perm()
+		}
+		err = ioutil.WriteFile(staging+Separator+v, content, mode)
+		if err != nil {
+			return err
+		}
+		changed = append(changed, v)
+	}
+
+	//refactoring the files which aren't part of the template
+	refactored, err := u.refactor(staging, dir, inNew, old, latest)
+	if err != nil {
+		return err
+	}
+	changed = append(changed, refactored...)
+
+	//listing the files removed from the template
+	err = filepath.Walk(tmp+Separator+"base", func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(tmp+Separator+"base", p)
+		if err != nil || inNew[rel] {
+			return err
+		}
+		if _, err := os.Stat(dir + Separator + rel); err == nil {
+			u.Files = append(u.Files, UpgradedFile{Path: rel, Status: FileRemovedFromTemplate})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(u.Files, func(i, j int) bool { return u.Files[i].Path < u.Files[j].Path })
+
+	//moving the changed files into the project
+	return commit(staging, dir, changed)
+}
+
+//refactor changes the imports of the old package and the license headers in the go files of the project in the given directory
+//to the latest package and license. The module path in the go.mod is changed with go mod edit. The files of the template
+//given are skipped since their changes are merged. The refactored files are written into the staging directory and returned.
+func (u *UpgradeReport) refactor(staging, dir string, template map[string]bool, old, latest Project) ([]string, error) {
+	/*
+	 * If the package and the license header didn't change, nothing has to be refactored
+	 * We will make the refactors of the package and the license header
+	 * We will refactor the go files in the project except the vendored and hidden ones
+	 * Then we will change the module path in the go.mod
+	 */
+	header := latest.License.Header()
+	if old.Package == latest.Package && old.License.Header() == header {
+		return nil, nil
+	}
+
+	//making the refactors
+	refactors := []generate.Refactor{}
+	if old.Package != latest.Package {
+		refactors = append(refactors, generate.Refactor{
+			Name:    BoilerPlatePackage,
+			Find:    `^"` + regexp.QuoteMeta(old.Package) + `(/|")`,
+			Replace: `"` + latest.Package + "${1}",
+			IsRegex: true,
+			Source:  generate.NewPackageRefactor(),
+		})
+	}
+	if old.License.Header() != header {
+		refactors = append(refactors, latest.LicenseRefactor())
+	}
+
+	//refactoring the go files
+	files := []string{}
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if p != dir && (info.Name() == "vendor" || strings.HasPrefix(info.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || template[rel] || filepath.Ext(rel) != ".go" || !info.Mode().IsRegular() {
+			return err
+		}
+		changed, err := refactorFile(p, staging+Separator+rel, refactors)
+		if err == nil && changed {
+			files = append(files, rel)
+		}
+		return err
+	})
+	if err != nil {
+		//error while refactoring the files of the project
+		fmt.Println("Error while refactoring the files of the project which aren't part of the template")
+		return nil, err
+	}
+
+	//changing the module path in the go.mod
+	if _, err := os.Stat(dir + Separator + "go.mod"); err == nil && old.Package != latest.Package && !template["go.mod"] {
+		b, err := ioutil.ReadFile(dir + Separator + "go.mod")
+		if err == nil {
+			err = ioutil.WriteFile(staging+Separator+"go.mod", b, 0644)
+		}
+		if err != nil {
+			return nil, err
+		}
+		c := exec.Command("go", "mod", "edit", "-module", latest.Package, "go.mod")
+		c.Dir = staging
+		out, err := c.CombinedOutput()
+		if err != nil {
+			//error while changing the module path
+			fmt.Println("Error while changing the module path in the go.mod to", latest.Package)
+			return nil, fmt.Errorf("go mod edit: %v %s", err, strings.TrimSpace(string(out)))
+		}
+		files = append(files, "go.mod")
+	}
+	for _, v := range files {
+		u.Files = append(u.Files, UpgradedFile{Path: v, Status: FileRefactored})
+	}
+	return files, nil
+}
+
+//refactorFile makes the given refactors in the go file and writes it to the given destination if it changed.
+//The imports are written back only if the package refactor matched so that the rest of the file is left as it is.
+func refactorFile(file, dst string, refactors []generate.Refactor) (bool, error) {
+	original, err := ioutil.ReadFile(file)
+	if err != nil {
+		return false, err
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return false, err
+	}
+	f := generate.NewFile(file, original)
+	f.Mode = info.Mode().Perm()
+	for i := range refactors {
+		err = refactors[i].DoFile(context.Background(), f)
+		if err != nil {
+			return false, err
+		}
+		if refactors[i].Name == BoilerPlatePackage && refactors[i].Matches == 0 {
+			f.SetBytes(original)
+		}
+	}
+	b, err := f.Bytes()
+	if err != nil || bytes.Equal(b, original) {
+		return false, err
+	}
+	err = os.MkdirAll(filepath.Dir(dst), 0775)
+	if err != nil {
+		return false, err
+	}
+	return true, f.Write(dst)
+}
+
+//mergeFile three way merges the file at the given path relative to the project. It returns the content of the file
+//to be written into the project. The content is nil if the file in the project needn't be changed.
+func mergeFile(rel, base, current, latest string) (*UpgradedFile, []byte, error) {
+	/*
+	 * We will read the three versions of the file
+	 * If the file is new, it is added
+	 * If the file was deleted from the project, it is left deleted
+	 * If the file wasn't changed in the project or in the template, we will take the changed one
+	 * Else we will merge the changes
+	 */
+	//reading the files
+	baseC, hasBase, err := readIfExists(base)
+	if err != nil {
+		return nil, nil, err
+	}
+	currentC, hasCurrent, err := readIfExists(current)
+	if err != nil {
+		return nil, nil, err
+	}
+	latestC, _, err := readIfExists(latest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f := &UpgradedFile{Path: rel, Status: FileUnchanged}
+	switch {
+	case !hasCurrent && !hasBase:
+		f.Status = FileAdded
+		return f, latestC, nil
+	case !hasCurrent && bytes.Equal(baseC, latestC):
+		return f, nil, nil
+	case !hasCurrent:
+		f.Status = FileDeletedLocally
+		return f, nil, nil
+	case bytes.Equal(currentC, latestC):
+		return f, nil, nil
+	case rel == MetadataFile || (hasBase && bytes.Equal(currentC, baseC)):
+		f.Status = FileUpdated
+		return f, latestC, nil
+	case hasBase && bytes.Equal(baseC, latestC):
+		return f, nil, nil
+	}
+
+	//merging the changes
+	merged, conflicts, err := merge3(current, base, latest)
+	if err != nil {
+		return nil, nil, err
+	}
+	f.Status, f.Conflicts = FileMerged, conflicts
+	if conflicts > 0 {
+		f.Status = FileConflicted
+	}
+	return f, merged, nil
+}
+
+//merge3 merges the changes from the base to the latest file into the current file using git merge-file.
+//A missing base is taken as an empty file. It returns the merged content and the number of conflicts in it.
+func merge3(current, base, latest string) ([]byte, int, error) {
+	if _, err := os.Stat(base); os.IsNotExist(err) {
+		base = os.DevNull
+	}
+	c := exec.Command("git", "merge-file", "-p", "-L", "project", "-L", "base", "-L", "template", current, base, latest)
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	c.Stdout, c.Stderr = out, errOut
+	err := c.Run()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) && exit.ExitCode() > 0 && exit.ExitCode() < 128 {
+		return out.Bytes(), exit.ExitCode(), nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("git merge-file %s: %v %s", current, err, errOut.String())
+	}
+	return out.Bytes(), 0, nil
+}
+
+//readIfExists reads the given file. It also tells whether the file exists.
+func readIfExists(file string) ([]byte, bool, error) {
+	b, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	return b, err == nil, err
+}
@@ -0,0 +1,235 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/project"
+)
+
+/*
+ * This file contains the tests written for the source code in upgrade.go
+ */
+
+//lines is the content of the files in the first version of the template used for the upgrade tests
+const lines = "one\ntwo\nthree\nfour\nfive\n"
+
+var upgradetcs = []struct {
+	Name     string
+	File     string
+	Status   project.UpgradeStatus
+	Validate func(content string, exists bool) bool
+}{
+	{"File unchanged in the project is updated", "a.txt", project.FileUpdated, func(c string, ok bool) bool {
+		return c == "one\ntwo\nthree\nfour\nFIVE\n"
+	}},
+	{"Changes on different lines are merged", "b.txt", project.FileMerged, func(c string, ok bool) bool {
+		return c == "ONE\ntwo\nthree\nfour\nFIVE\n"
+	}},
+	{"Changes on the same line conflict", "c.txt", project.FileConflicted, func(c string, ok bool) bool {
+		return strings.Contains(c, "<<<<<<< project\nmine\n") && strings.Contains(c, ">>>>>>> template\n") &&
+			strings.Contains(c, "theirs\n")
+	}},
+	{"File deleted in the project stays deleted", "d.txt", project.FileDeletedLocally, func(c string, ok bool) bool {
+		return !ok
+	}},
+	{"File new in the template is added", "e.txt", project.FileAdded, func(c string, ok bool) bool {
+		return c == "new\n"
+	}},
+	{"Metadata records the new version of the template", project.MetadataFile, project.FileUpdated, func(c string, ok bool) bool {
+		return strings.Contains(c, `"commit"`)
+	}},
+}
+
+//TestUpgrade generates a project from a git template, changes both of them and upgrades the project
+func TestUpgrade(t *testing.T) {
+	/*
+	 * We will create the first version of the template
+	 * Then we will generate the project from it and change the project
+	 * Then we will make the second version of the template
+	 * Then we will upgrade the project and check each of the files
+	 */
+	skipWithoutGit(t)
+	//creating the first version of the template
+	repo := t.TempDir()
+	runGit(t, repo, "init", "--quiet")
+	manifest := "files:\n  - path: a.txt\n  - path: b.txt\n  - path: c.txt\n  - path: d.txt\n"
+	writeFile(filepath.Join(repo, "manifest.yaml"), manifest)
+	for _, v := range []string{"a.txt", "b.txt", "c.txt", "d.txt"} {
+		writeFile(filepath.Join(repo, v), lines)
+	}
+	runGit(t, repo, "add", "-A")
+	runGit(t, repo, "commit", "--quiet", "-m", "v1")
+
+	//generating the project and changing it
+	dst := filepath.Join(t.TempDir(), "orders")
+	p := testProject(dst)
+	p.Template = project.Template{Source: repo}
+	err := p.Setup()
+	if err != nil {
+		t.Fatal("Error while generating the project", err)
+	}
+	writeFile(filepath.Join(dst, "b.txt"), "ONE\ntwo\nthree\nfour\nfive\n")
+	writeFile(filepath.Join(dst, "c.txt"), "one\ntwo\nmine\nfour\nfive\n")
+	os.Remove(filepath.Join(dst, "d.txt"))
+
+	//making the second version of the template
+	writeFile(filepath.Join(repo, "manifest.yaml"), manifest+"  - path: e.txt\n")
+	writeFile(filepath.Join(repo, "a.txt"), "one\ntwo\nthree\nfour\nFIVE\n")
+	writeFile(filepath.Join(repo, "b.txt"), "one\ntwo\nthree\nfour\nFIVE\n")
+	writeFile(filepath.Join(repo, "c.txt"), "one\ntwo\ntheirs\nfour\nfive\n")
+	writeFile(filepath.Join(repo, "d.txt"), "one\n")
+	writeFile(filepath.Join(repo, "e.txt"), "new\n")
+	runGit(t, repo, "add", "-A")
+	runGit(t, repo, "commit", "--quiet", "-m", "v2")
+
+	//upgrading the project
+	report, err := project.Upgrade(dst, nil)
+	if err != nil {
+		t.Fatal("Error while upgrading the project", err)
+	}
+	statuses := map[string]project.UpgradedFile{}
+	for _, v := range report.Files {
+		statuses[v.Path] = v
+	}
+	for _, v := range upgradetcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			if statuses[v.File].Status != v.Status {
+				t.Errorf("Expected %s to be %s. Got %s", v.File, v.Status, statuses[v.File].Status)
+			}
+			b, err := ioutil.ReadFile(filepath.Join(dst, v.File))
+			if !v.Validate(string(b), err == nil) {
+				t.Errorf("Unexpected content of %s after the upgrade\n%s", v.File, string(b))
+			}
+		})
+	}
+	if c := report.Conflicts(); len(c) != 1 || c[0].Conflicts != 1 {
+		t.Error("Expected one conflict in c.txt. Got", c)
+	}
+}
+
+//TestRegenerate generates a project from the built in template, changes it and regenerates it with a new package
+func TestRegenerate(t *testing.T) {
+	/*
+	 * We will generate the project and change it
+	 * Then we will regenerate it with a new package
+	 * Then we will check the new package is used across the tree without losing the changes
+	 */
+	skipWithoutGit(t)
+	project.Templates = os.DirFS(templatesDir)
+	dst := filepath.Join(t.TempDir(), "orders")
+	err := testProject(dst).Setup()
+	if err != nil {
+		t.Fatal("Error while generating the project", err)
+	}
+	main, err := ioutil.ReadFile(filepath.Join(dst, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(filepath.Join(dst, "main.go"), string(main)+"\n//added by the user\n")
+
+	_, err = project.Regenerate(dst, project.Project{Package: "github.com/jane/shop"})
+	if err != nil {
+		t.Fatal("Error while regenerating the project", err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dst, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"github.com/jane/shop/routes"`) || strings.Contains(string(b), "github.com/jane/orders") {
+		t.Error("Expected main.go to import the new package. Got\n", string(b))
+	}
+	if !strings.Contains(string(b), "//added by the user") {
+		t.Error("Expected the changes in main.go to be kept. Got\n", string(b))
+	}
+	m, err := project.ReadMetadata(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Project.Package != "github.com/jane/shop" || m.Project.Name != "Orders" {
+		t.Error("Expected the metadata to record the new package and the old name. Got", m.Project)
+	}
+}
+
+//TestRegenerateTree regenerates a project having the files added by the generators and a go.mod with a new package
+//and license. Then it checks that the files outside the template and the module path use them and that the project still builds.
+func TestRegenerateTree(t *testing.T) {
+	/*
+	 * We will generate the project and add the route and the OpenAPI routes to it
+	 * We will initialize its modules
+	 * Then we will regenerate it with a new package and license
+	 * Then we will check the files added by the generators and the go.mod and build the project
+	 */
+	skipWithoutGit(t)
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not available")
+	}
+	project.Templates = os.DirFS(templatesDir)
+	env, build := harnessEnv(t)
+	dst := filepath.Join(t.TempDir(), "orders")
+	p := testProject(dst)
+	p.Components = &project.Components{}
+	var added []string
+	err := p.Setup()
+	if err == nil {
+		added, err = project.AddRoute(dst, project.RouteSpec{Version: "v1", Pattern: "/orders", Method: "POST", Register: true})
+	}
+	if err == nil {
+		_, err = project.SyncOpenAPI(dst, petStore)
+	}
+	if err != nil {
+		t.Fatal("Error while generating the project", err)
+	}
+	handler := filepath.ToSlash(added[0])
+
+	//initializing the modules
+	if build {
+		buildVariant(t, p, env)
+	} else {
+		writeFile(filepath.Join(dst, "go.mod"), "module "+p.Package+"\n\ngo 1.16\n")
+	}
+
+	//regenerating the project
+	report, err := project.Regenerate(dst, project.Project{Package: "github.com/jane/renamed", License: project.License{Type: project.BSD3}})
+	if err != nil {
+		t.Fatal("Error while regenerating the project", err)
+	}
+	refactored := map[string]bool{}
+	for _, v := range report.Files {
+		if v.Status == project.FileRefactored {
+			refactored[filepath.ToSlash(v.Path)] = true
+		}
+	}
+	for _, v := range []string{"go.mod", handler, "routes/openapi_gen.go", "api/openapi_gen.go"} {
+		if !refactored[v] {
+			t.Error("Expected", v, "to be refactored. Got", report.Files)
+		}
+	}
+
+	//checking the files
+	if mod := readFile(t, filepath.Join(dst, "go.mod")); !strings.Contains(mod, "module github.com/jane/renamed\n") {
+		t.Error("Expected the module path to be the new package. Got\n", mod)
+	}
+	for _, v := range []string{handler, "routes/openapi_gen.go"} {
+		c := readFile(t, filepath.Join(dst, v))
+		if strings.Contains(c, p.Package) || !strings.Contains(c, `"github.com/jane/renamed/routes/response"`) {
+			t.Error("Expected", v, "to import the new package. Got\n", c)
+		}
+		if !strings.Contains(c, "SPDX-License-Identifier: BSD-3-Clause") || strings.Contains(c, "MIT") {
+			t.Error("Expected", v, "to have the new license header. Got\n", c)
+		}
+	}
+	if build {
+		checkVariant(t, dst, env)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+/*
+ * This file contains the validations of the project details
+ */
+
+//ValidationError is the error returned when the project details are invalid
+type ValidationError struct {
+	//Errors are the errors of each invalid detail
+	Errors []error
+}
+
+//Error is the error implementation of the validation error
+func (v ValidationError) Error() string {
+	msgs := []string{}
+	for _, e := range v.Errors {
+		msgs = append(msgs, e.Error())
+	}
+	return "the project details are invalid:\n  " + strings.Join(msgs, "\n  ")
+}
+
+//Validate validates the details of the project. The package has to be a valid module path, the author email
+//a valid email, the license year 4 digits, the license type one of the licenses of the project if the template
+//has any and the destination writable. The template of the project has to be resolved before.
+func (p Project) Validate() error {
+	/*
+	 * We will find the licenses of the project
+	 * Then we will validate each of the details
+	 */
+	checks := []error{
+		ValidatePackage(p.Package),
+		ValidateEmail(p.Author.Email),
+		ValidateYear(p.License.Year),
+		ValidateDestination(p.Destination),
+	}
+	licenses, err := p.Licenses()
+	if err != nil {
+		checks = append(checks, err)
+	} else if len(licenses) > 0 {
+		checks = append(checks, ValidateLicenseType(p.License.Type, licenses))
+	}
+	errs := []error{}
+	for _, err := range checks {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return ValidationError{Errors: errs}
+}
+
+//ValidatePackage validates that the package is a valid module path
+func ValidatePackage(pkg string) error {
+	if len(pkg) == 0 {
+		return errors.New("package is required")
+	}
+	if err := module.CheckImportPath(pkg); err != nil {
+		return fmt.Errorf("package %q is not a valid module path: %v", pkg, err)
+	}
+	return nil
+}
+
+//ValidateEmail validates that the given email is a plain email address like jane@example.com
+func ValidateEmail(email string) error {
+	a, err := mail.ParseAddress(email)
+	if err != nil || a.Address != email {
+		return fmt.Errorf("author email %q is not a valid email address like jane@example.com", email)
+	}
+	return nil
+}
+
+//year matches a 4 digit year
+var year = regexp.MustCompile(`^[0-9]{4}$`)
+
+//ValidateYear validates that the given copyright year has 4 digits
+func ValidateYear(y string) error {
+	if !year.MatchString(y) {
+		return fmt.Errorf("license year %q is not a 4 digit year", y)
+	}
+	return nil
+}
+
+//ValidateLicenseType validates that the given license type is one of the given types
+func ValidateLicenseType(l LicenseType, types []LicenseType) error {
+	for _, v := range types {
+		if v == l {
+			return nil
+		}
+	}
+	names := []string{}
+	for _, v := range types {
+		names = append(names, string(v))
+	}
+	return fmt.Errorf("license type %q is not one of %s", l, strings.Join(names, ", "))
+}
+
+//ValidateDestination validates that the project can be written into the destination. The destination, or its
+//nearest existing parent if it doesn't exist yet, has to be a writable directory.
+func ValidateDestination(dst string) error {
+	/*
+	 * We will find the nearest existing directory
+	 * Then we will check that a file can be created in it
+	 */
+	if len(dst) == 0 {
+		return errors.New("destination is required")
+	}
+	dir := dst
+	for {
+		s, err := os.Stat(dir)
+		if err == nil && !s.IsDir() {
+			return fmt.Errorf("destination %s is not a directory", dir)
+		}
+		if err == nil {
+			break
+		}
+		if !os.IsNotExist(err) || dir == filepath.Dir(dir) {
+			return fmt.Errorf("destination %s is not accessible: %v", dst, err)
+		}
+		dir = filepath.Dir(dir)
+	}
+	f, err := ioutil.TempFile(dir, ".web-starter-")
+	if err != nil {
+		return fmt.Errorf("destination %s is not writable: %v", dst, err)
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return nil
+}
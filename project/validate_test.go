@@ -0,0 +1,64 @@
+// Copyright 2019 Cuttle.ai. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cuttle-ai/web-starter/project"
+)
+
+/*
+ * This file contains the tests written for the source code in validate.go
+ */
+
+var validatetcs = []struct {
+	Name    string
+	Setup   func(p *project.Project)
+	Invalid int
+}{
+	{"Valid project", func(p *project.Project) {}, 0},
+	{"Package with spaces", func(p *project.Project) { p.Package = "github.com/jane/my orders" }, 1},
+	{"Empty package", func(p *project.Project) { p.Package = "" }, 1},
+	{"Email without @", func(p *project.Project) { p.Author.Email = "jane.example.com" }, 1},
+	{"Email with name", func(p *project.Project) { p.Author.Email = "Jane <jane@example.com>" }, 1},
+	{"Non numeric year", func(p *project.Project) { p.License.Year = "twenty" }, 1},
+	{"Two digit year", func(p *project.Project) { p.License.Year = "19" }, 1},
+	{"Unknown license", func(p *project.Project) { p.License.Type = "WTFPL" }, 1},
+	{"Destination is a file", func(p *project.Project) {
+		writeFile(p.Destination, "not a directory")
+	}, 1},
+	{"Destination under a file", func(p *project.Project) {
+		writeFile(p.Destination, "not a directory")
+		p.Destination = filepath.Join(p.Destination, "orders")
+	}, 1},
+	{"Everything invalid", func(p *project.Project) {
+		*p = project.Project{}
+	}, 5},
+}
+
+//TestValidate is the test suite for validating the project details
+func TestValidate(t *testing.T) {
+	project.Templates = os.DirFS(templatesDir)
+	for _, v := range validatetcs {
+		t.Run(v.Name, func(t *testing.T) {
+			fmt.Println("Testing", v.Name)
+			p := testProject(filepath.Join(t.TempDir(), "orders"))
+			v.Setup(&p)
+			err := p.Validate()
+			var vErr project.ValidationError
+			if v.Invalid == 0 && err != nil {
+				t.Error("Expected the project to be valid. Got", err)
+			}
+			if v.Invalid > 0 && (!errors.As(err, &vErr) || len(vErr.Errors) != v.Invalid) {
+				t.Error("Expected", v.Invalid, "invalid details. Got", err)
+			}
+		})
+	}
+}
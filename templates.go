@@ -0,0 +1,10 @@
+package main
+
+import "embed"
+
+/* This file contains the boilerplate code and license templates embedded in the application */
+
+//templates has the boilerplate code, the license templates and their manifest used for generating the projects
+//along with the scaffolds of the generators adding routes, handlers and models to them
+//go:embed all:boilerplate licenses scaffolds manifest.yaml
+var templates embed.FS